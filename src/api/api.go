@@ -1,15 +1,19 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 	"pkv/api/src/domain"
+	"pkv/api/src/repository/dpv"
 	"pkv/api/src/repository/graph"
 	"pkv/api/src/repository/t"
 	"pkv/api/src/service/user"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ErrorResponse struct {
@@ -100,6 +104,39 @@ func RequireGlobalAdmin(r *http.Request, db *graph.Db) (*domain.User, error) {
 	return user, nil
 }
 
+// RequireOrganiser checks that the current user organises the training, administers one of its organisers or is a
+// global administrator. It returns the key of the current user.
+func RequireOrganiser(training domain.TrainingDTO, r *http.Request, db *graph.Db) (string, error) {
+	key, err := Authenticated(r)
+	if err != nil {
+		return "", t.Errorf("authentication failed: %w", err)
+	}
+	organisers := make(map[string]bool)
+	for _, organiser := range training.OrganiserKeys {
+		if organiser == key {
+			return key, nil
+		}
+		organisers[organiser] = true
+	}
+	users, err := db.GetAdministeredUsers(key, r.Context())
+	if err != nil {
+		return "", t.Errorf("cannot get list of administered users: %w", err)
+	}
+	for _, u := range users {
+		if organisers[u.Key] {
+			return key, nil
+		}
+	}
+	user, err := db.Users.Read(key, r.Context())
+	if err != nil {
+		return "", t.Errorf("reading current user failed: %w", err)
+	}
+	if !IsAdmin(*user) {
+		return "", t.Errorf("you do not organise training %s", training.Key)
+	}
+	return key, nil
+}
+
 func SuccessJson(w http.ResponseWriter, r *http.Request, data interface{}) {
 	jsonMsg, err := json.Marshal(data)
 	if err != nil {
@@ -125,8 +162,11 @@ func Success(w http.ResponseWriter, r *http.Request, jsonMsg []byte) {
 	)
 }
 
+// Error responds with the message of the error in the language of the client, see Language, and logs it in English
 func Error(w http.ResponseWriter, r *http.Request, err error, code int) {
+	language := Language(r)
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", language)
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.WriteHeader(code)
 	if err == nil {
@@ -134,7 +174,7 @@ func Error(w http.ResponseWriter, r *http.Request, err error, code int) {
 	}
 	logErr := err
 	errorMsgJSON, err := json.Marshal(ErrorResponse{
-		err.Error(),
+		t.Localise(err, language),
 	})
 	if err != nil {
 		log.Println(err)
@@ -154,6 +194,158 @@ func Error(w http.ResponseWriter, r *http.Request, err error, code int) {
 	)
 }
 
+// Language returns the language for messages to the client: the language query parameter, or else the most preferred
+// language of the Accept-Language header, if messages are available in it. Messages are in English otherwise.
+func Language(r *http.Request) string {
+	if language := r.URL.Query().Get("language"); language != "" && t.Supported(language) {
+		return strings.ToLower(language)
+	}
+	for _, language := range t.Preferences(r.Header.Get("Accept-Language")) {
+		if t.Supported(language) {
+			return language
+		}
+	}
+	return t.Source
+}
+
+// DescriptionProjection returns a function reducing descriptions to the one best matching the language query parameter,
+// or else the languages of the Accept-Language header, falling back to the configured languages in order. The language
+// served is the only one left, whether it is machine translated is part of the description. The Content-Language
+// header lists the languages served, most often one. Descriptions are kept complete with include=descriptions.
+func DescriptionProjection(w http.ResponseWriter, r *http.Request) func(domain.Descriptions) {
+	w.Header().Add("Vary", "Accept-Language")
+	if _, ok := MakeSet(r.URL.Query().Get("include"))["descriptions"]; ok {
+		return func(domain.Descriptions) {}
+	}
+	preferred, fallback := descriptionLanguages(r)
+	var served []string
+	return func(descriptions domain.Descriptions) {
+		if language, ok := descriptions.Negotiate(preferred, fallback); ok {
+			descriptions.Only(language)
+			if !slices.Contains(served, language) {
+				served = append(served, language)
+				w.Header().Set("Content-Language", strings.Join(served, ", "))
+			}
+		}
+	}
+}
+
+// DescriptionLanguage returns the configured language best matching the language query parameter, or else the
+// languages of the Accept-Language header, falling back to the first configured language. Searches analyse the text in
+// this language.
+func DescriptionLanguage(r *http.Request) string {
+	preferred, configured := descriptionLanguages(r)
+	for _, language := range preferred {
+		language = strings.ToLower(language)
+		base, _, _ := strings.Cut(language, "-")
+		for _, candidate := range []string{language, base} {
+			if slices.Contains(configured, candidate) {
+				return candidate
+			}
+		}
+	}
+	if len(configured) == 0 {
+		return ""
+	}
+	return configured[0]
+}
+
+// descriptionLanguages returns the languages the client prefers for descriptions, the language query parameter coming
+// first, and the configured languages in order
+func descriptionLanguages(r *http.Request) ([]string, []string) {
+	var preferred []string
+	if language := r.URL.Query().Get("language"); language != "" {
+		preferred = append(preferred, language)
+	}
+	preferred = append(preferred, t.Preferences(r.Header.Get("Accept-Language"))...)
+	var configured []string
+	for _, language := range dpv.ConfigInstance.Settings.Languages {
+		configured = append(configured, language.Key)
+	}
+	return preferred, configured
+}
+
+// ProjectTraining applies the description projection to a training, its location and its organisers
+func ProjectTraining(project func(domain.Descriptions), training domain.TrainingDTO) {
+	project(training.Descriptions)
+	if training.Location != nil {
+		project(training.Location.Descriptions)
+	}
+	for _, organiser := range training.Organisers {
+		project(organiser.Descriptions)
+	}
+}
+
+// ProjectEvent applies the description projection to an event, its locations and its organisers
+func ProjectEvent(project func(domain.Descriptions), event domain.EventDTO) {
+	project(event.Descriptions)
+	for _, location := range event.Locations {
+		project(location.Descriptions)
+	}
+	for _, organiser := range event.Organisers {
+		project(organiser.Descriptions)
+	}
+}
+
+// ParseEventTimespan reads the from and to query parameters of event lists, both being inclusive dates. From defaults
+// to today, to is returned as an exclusive value and stays zero if it is not given.
+func ParseEventTimespan(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	from, err := ParseDate(query.Get("from"))
+	if err != nil {
+		return from, from, t.Errorf("invalid from: %w", err)
+	}
+	to, err := ParseDate(query.Get("to"))
+	if err != nil {
+		return from, to, t.Errorf("invalid to: %w", err)
+	}
+	if from.IsZero() {
+		from = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+// WriteOptions travel with the context of a write to the hooks checking the item. Problems that do not fail the write
+// are collected as warnings to be returned to the client.
+type WriteOptions struct {
+	RejectConflicts bool // whether conflicts with other trainings fail the write instead of being warned about
+	Warnings        []error
+}
+
+type writeOptionsKey struct{}
+
+// RequestWriteOptions reads the reject_conflicts query parameter, which defaults to the reject_conflicts setting
+func RequestWriteOptions(r *http.Request) *WriteOptions {
+	options := &WriteOptions{RejectConflicts: dpv.ConfigInstance != nil && dpv.ConfigInstance.Settings.RejectConflicts}
+	if reject, err := strconv.ParseBool(r.URL.Query().Get("reject_conflicts")); err == nil {
+		options.RejectConflicts = reject
+	}
+	return options
+}
+
+// WithWriteOptions returns a context carrying the options, see WriteOptionsOf
+func WithWriteOptions(ctx context.Context, options *WriteOptions) context.Context {
+	return context.WithValue(ctx, writeOptionsKey{}, options)
+}
+
+// WriteOptionsOf returns the options the context carries, or nil for writes that are not requested by a client
+func WriteOptionsOf(ctx context.Context) *WriteOptions {
+	options, _ := ctx.Value(writeOptionsKey{}).(*WriteOptions)
+	return options
+}
+
+// LocaliseAll translates the errors into the language, e.g. warnings for a response
+func LocaliseAll(errs []error, language string) []string {
+	var texts []string
+	for _, err := range errs {
+		texts = append(texts, t.Localise(err, language))
+	}
+	return texts
+}
+
 func MakeSet(queryParam string) map[string]struct{} {
 	set := make(map[string]struct{})
 	if queryParam != "" {
@@ -178,3 +370,54 @@ func ParseFloat(queryValue string) (float64, error) {
 	}
 	return strconv.ParseFloat(queryValue, 64)
 }
+
+func ParseDate(queryValue string) (time.Time, error) {
+	if queryValue == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, queryValue)
+}
+
+// ParseTrainingQueryOptions reads the filters of the list of trainings from the query parameters
+func ParseTrainingQueryOptions(r *http.Request) (domain.TrainingQueryOptions, error) {
+	query := r.URL.Query()
+	weekday, err := ParseInt(query.Get("weekday"))
+	if err != nil {
+		return domain.TrainingQueryOptions{}, t.Errorf("invalid weekday: %w", err)
+	}
+	skip, err := ParseInt(query.Get("skip"))
+	if err != nil {
+		return domain.TrainingQueryOptions{}, t.Errorf("invalid skip: %w", err)
+	}
+	limit, err := ParseInt(query.Get("limit"))
+	if err != nil {
+		return domain.TrainingQueryOptions{}, t.Errorf("invalid limit: %w", err)
+	}
+	lat, err := ParseFloat(query.Get("lat"))
+	if err != nil {
+		return domain.TrainingQueryOptions{}, t.Errorf("invalid lat: %w", err)
+	}
+	lng, err := ParseFloat(query.Get("lng"))
+	if err != nil {
+		return domain.TrainingQueryOptions{}, t.Errorf("invalid lng: %w", err)
+	}
+	maxDistance, err := ParseFloat(query.Get("maxDistance"))
+	if err != nil {
+		return domain.TrainingQueryOptions{}, t.Errorf("invalid maxDistance: %w", err)
+	}
+	return domain.TrainingQueryOptions{
+		City:         query.Get("city"),
+		Weekday:      weekday,
+		OrganiserKey: query.Get("organiser"),
+		LocationKey:  query.Get("location"),
+		Lat:          lat,
+		Lng:          lng,
+		MaxDistance:  maxDistance,
+		Type:         query.Get("type"),
+		Text:         query.Get("text"),
+		Language:     DescriptionLanguage(r),
+		Include:      MakeSet(query.Get("include")),
+		Skip:         skip,
+		Limit:        limit,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Attendee is a user registered for an occurrence, either attending or waiting for a place to become free
+type Attendee struct {
+	UserKey    string    `json:"userId" example:"123"`
+	Name       string    `json:"name,omitempty" example:"John Doe"`
+	Status     string    `json:"status" example:"attending"` // attending, waitlisted
+	Position   int       `json:"position,omitempty"`         // place on the waitlist, starting at 1
+	Registered time.Time `json:"registered"`
+}
+
+// Attendance lists the users registered for an occurrence of a training
+type Attendance struct {
+	Date       time.Time  `json:"date"` // RFC 3339 date of the occurrence
+	Capacity   int        `json:"capacity,omitempty"`
+	Attending  int        `json:"attending"`
+	Waitlisted int        `json:"waitlisted"`
+	Attendees  []Attendee `json:"attendees"`
+}
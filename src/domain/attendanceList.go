@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// AttendanceList records who actually took part in the occurrence of a training on Date
+type AttendanceList struct {
+	Entity
+	TrainingKey string    `json:"trainingId" example:"123"`
+	Date        time.Time `json:"date"`      // RFC 3339 date of the occurrence
+	UserKeys    []string  `json:"userIds"`   // checked-in users
+	Anonymous   int       `json:"anonymous"` // head count of participants without an account
+	RecordedBy  string    `json:"recordedBy,omitempty" example:"123"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// AttendanceReport aggregates the recorded attendance of trainings between From and To
+type AttendanceReport struct {
+	From      time.Time            `json:"from"` // RFC 3339 date, inclusive
+	To        time.Time            `json:"to"`   // RFC 3339 date, inclusive
+	Trainings []TrainingAttendance `json:"trainings"`
+	Total     TrainingAttendance   `json:"total"`
+}
+
+// TrainingAttendance sums up the attendance of a training, or of all trainings in the total of a report
+type TrainingAttendance struct {
+	Training     TrainingSummary `json:"training"`
+	Occurrences  int             `json:"occurrences"`  // scheduled occurrences
+	Recorded     int             `json:"recorded"`     // occurrences with an attendance list
+	Participants int             `json:"participants"` // checked-in users and anonymous participants of all occurrences
+	Members      int             `json:"members"`      // distinct checked-in users
+	Anonymous    int             `json:"anonymous"`
+	Average      float64         `json:"average"` // participants per recorded occurrence
+}
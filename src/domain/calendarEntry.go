@@ -0,0 +1,16 @@
+package domain
+
+// CalendarEntry is an occurrence within a calendar merging the occurrences of several trainings and events
+type CalendarEntry struct {
+	Occurrence
+	Training TrainingSummary `json:"training"`
+	Location *Location       `json:"location,omitempty"`
+}
+
+// TrainingSummary identifies the training or event of a CalendarEntry
+type TrainingSummary struct {
+	Key   string `json:"_key" example:"123"`
+	Type  string `json:"type,omitempty" example:"parkour-training"`
+	Title string `json:"title,omitempty" example:"Parkour im Park"`
+	Event bool   `json:"event,omitempty"` // the key refers to an event rather than a training
+}
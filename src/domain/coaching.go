@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Coaching is an edge from a user to a training, assigning the user as coach of the occurrence on Date instead of the
+// organisers of the training
+type Coaching struct {
+	Key   string    `json:"_key,omitempty"`
+	From  string    `json:"_from,omitempty"`
+	To    string    `json:"_to,omitempty"`
+	Label string    `json:"label,omitempty"`
+	Date  time.Time `json:"date"` // RFC 3339 date of the occurrence
+}
+
+// CoachingDTO enriches Coaching with the User who coaches
+type CoachingDTO struct {
+	Coaching
+	User *User `json:"user,omitempty"`
+}
+
+// Duty is an occurrence the user is responsible for, either as organiser or as substitute coach
+type Duty struct {
+	CalendarEntry
+	Substitute bool `json:"substitute,omitempty"` // whether the user covers for the organisers
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// Conflict is an overlap of two occurrences of different trainings at the same location
+type Conflict struct {
+	LocationKey string          `json:"locationId" example:"123"`
+	Start       time.Time       `json:"start"` // RFC 3339 date-time at which the overlap begins
+	End         time.Time       `json:"end"`   // RFC 3339 date-time at which the overlap ends
+	Entries     []CalendarEntry `json:"entries"`
+}
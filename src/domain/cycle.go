@@ -5,7 +5,9 @@ import "time"
 // Cycle represents recurring events
 type Cycle struct {
 	Weekday    int       `json:"weekday,omitempty"`
+	Weekdays   []int     `json:"weekdays,omitempty"`
 	Monthday   int       `json:"monthday,omitempty"`
+	Month      int       `json:"month,omitempty"`
 	Interval   int       `json:"interval,omitempty"`
 	Begin      int       `json:"begin,omitempty"`     // seconds
 	Duration   int       `json:"duration,omitempty"`  // seconds
@@ -47,6 +49,27 @@ Weekday = 0
 Monthday = 3
 Interval = 0 | 1
 
+Am letzten Tag jedes Monats:
+Weekday = 0
+Monthday = -1
+Interval = 0 | 1
+
+Jeden Montag und Donnerstag:
+Weekdays = [1, 4]
+Monthday = 0
+Interval = 0 | 1
+
+Jedes Jahr am ersten Samstag im Juni:
+Weekday = 6
+Monthday = 1
+Month = 6
+Interval = 0 | 1
+
+Weekdays is combined with Weekday, so a cycle takes place on all weekdays of both. Weekly cycles with an Interval
+count the weeks from Monday to Sunday, starting with the week of the first occurrence. Likewise, monthly and yearly
+cycles (Month = 1 to 12) count the months and years starting with the first occurrence, skipping those without
+the day.
+
 Weekday = 0 kombiniert mit Interval divisible by 7 ist verboten
 Trainings, die auch am Freitag stattfinden: Weekday in [0, 5]
 
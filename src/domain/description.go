@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"slices"
+	"strings"
+)
+
 // Descriptions are maps of language code to title and text
 type Descriptions map[string]Description // per language
 
@@ -7,5 +12,50 @@ type Description struct {
 	Title      string `json:"title,omitempty" example:"My Item"`
 	Text       string `json:"text,omitempty" example:"Something to describe"`
 	Render     string `json:"render,omitempty" example:"<p>Something to describe</p>"`
-	Translated bool   `json:"translated,omitempty" example:"false"` // whether this has automatically been translated from another language
+	Translated bool   `json:"translated,omitempty" example:"false"`                           // whether this has automatically been translated from another language
+	Source     string `json:"source,omitempty" example:"de:9e107d9d372bb6826bd81d3542a419d6"` // language and checksum of the description translated from
+}
+
+// Described is implemented by entities that carry descriptions
+type Described interface {
+	GetDescriptions() Descriptions
+}
+
+// Negotiate returns the language of the description best matching the preferred languages, which are tried in order
+// together with their base languages, e.g. de for de-AT, before the fallback languages and then any other language.
+// Descriptions without title and text are ignored. It returns false if there is no description.
+func (d Descriptions) Negotiate(preferred []string, fallback []string) (string, bool) {
+	var candidates []string
+	for _, language := range preferred {
+		language = strings.ToLower(language)
+		candidates = append(candidates, language)
+		if base, _, found := strings.Cut(language, "-"); found {
+			candidates = append(candidates, base)
+		}
+	}
+	candidates = append(candidates, fallback...)
+	for _, language := range candidates {
+		if description, ok := d[language]; ok && (description.Title != "" || description.Text != "") {
+			return language, true
+		}
+	}
+	languages := make([]string, 0, len(d))
+	for language, description := range d {
+		if description.Title != "" || description.Text != "" {
+			languages = append(languages, language)
+		}
+	}
+	if len(languages) == 0 {
+		return "", false
+	}
+	return slices.Min(languages), true
+}
+
+// Only removes all descriptions except the one in the language
+func (d Descriptions) Only(language string) {
+	for l := range d {
+		if l != language {
+			delete(d, l)
+		}
+	}
 }
@@ -0,0 +1,39 @@
+package domain
+
+import "testing"
+
+func TestDescriptions_Negotiate(t *testing.T) {
+	descriptions := Descriptions{
+		"de": {Title: "Training"},
+		"en": {Title: "Training", Translated: true},
+		"fr": {},
+		"it": {Title: "Allenamento", Translated: true},
+	}
+	fallback := []string{"de", "en"}
+	tests := []struct {
+		name      string
+		d         Descriptions
+		preferred []string
+		want      string
+		wantOk    bool
+	}{
+		{"preferred", descriptions, []string{"en", "de"}, "en", true},
+		{"base language", descriptions, []string{"it-ch"}, "it", true},
+		{"empty description", descriptions, []string{"fr"}, "de", true},
+		{"fallback", descriptions, []string{"ru"}, "de", true},
+		{"any other", Descriptions{"nl": {Title: "Training"}, "it": {Title: "Allenamento"}}, nil, "it", true},
+		{"nothing", Descriptions{"fr": {}}, []string{"fr"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.d.Negotiate(tt.preferred, fallback)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Negotiate() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+	descriptions.Only("it")
+	if _, ok := descriptions["it"]; len(descriptions) != 1 || !ok {
+		t.Errorf("Only() = %v, want the Italian description", descriptions)
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Event stores information about a one-off event such as a jam, a workshop or a competition, which may last several days
+type Event struct {
+	Entity
+	Type                 string            `json:"type,omitempty" example:"parkour-jam"` // parkour-jam, workshop, competition, show, meeting, tour
+	Information          map[string]string `json:"information,omitempty"`
+	Descriptions         Descriptions      `json:"descriptions,omitempty"`
+	Start                time.Time         `json:"start"`                                      // RFC 3339 date-time
+	End                  time.Time         `json:"end"`                                        // RFC 3339 date-time after Start
+	Timezone             string            `json:"timezone,omitempty" example:"Europe/Berlin"` // IANA time zone, overrides the one of the first location
+	RegistrationDeadline *time.Time        `json:"registrationDeadline,omitempty"`             // RFC 3339 date-time, no later than End
+	ParticipantLimit     int               `json:"participantLimit,omitempty"`                 // 0 for no limit
+	Price                float64           `json:"price,omitempty" example:"15"`
+	Currency             string            `json:"currency,omitempty" example:"EUR"` // ISO 4217 code, defaults to EUR
+	Photos
+	Comments []Comment `json:"comments,omitempty"`
+}
+
+func (e *Event) GetDescriptions() Descriptions {
+	return e.Descriptions
+}
+
+func (e *Event) GetComments() []Comment {
+	return e.Comments
+}
+
+func (e *Event) SetComments(comments []Comment) {
+	e.Comments = comments
+}
@@ -0,0 +1,10 @@
+package domain
+
+// EventDTO enriches Event with the locations it takes place at and its organisers
+type EventDTO struct {
+	Event
+	Locations     []Location `json:"locations,omitempty"`
+	LocationKeys  []string   `json:"locationIds,omitempty" example:"123"`
+	OrganiserKeys []string   `json:"organiserIds,omitempty" example:"123"`
+	Organisers    []User     `json:"organisers,omitempty"`
+}
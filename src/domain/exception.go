@@ -8,4 +8,5 @@ type Exception struct {
 	Begin      int       `json:"begin,omitempty"`    // seconds
 	Duration   int       `json:"duration,omitempty"` // seconds, use 0 to cancel
 	LocationId string    `json:"locationId,omitempty" example:"location/123"`
+	Reason     string    `json:"reason,omitempty" example:"Ostermontag"`
 }
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Holiday is a day without school or work in a German state
+type Holiday struct {
+	Date   time.Time `json:"date"` // RFC 3339 date
+	Name   string    `json:"name" example:"Ostermontag"`
+	School bool      `json:"school,omitempty"` // part of the school holidays rather than a public holiday
+}
+
+// HolidayPause lets a training pause on the public holidays and during the school holidays of a German state
+type HolidayPause struct {
+	State  string `json:"state" example:"NW"` // ISO 3166-2 code of the state without the DE- prefix
+	Public bool   `json:"public,omitempty"`
+	School bool   `json:"school,omitempty"`
+}
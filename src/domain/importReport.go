@@ -0,0 +1,18 @@
+package domain
+
+// ImportReport lists what happened to every event of an imported calendar
+type ImportReport struct {
+	Events []ImportedEvent `json:"events"`
+}
+
+// ImportedEvent is the outcome of importing a single event, its status being "created", "updated" or "rejected"
+type ImportedEvent struct {
+	UID      string   `json:"uid" example:"abc123@google.com"`
+	Summary  string   `json:"summary,omitempty" example:"Parkour Training"`
+	Status   string   `json:"status" example:"created"`
+	Key      string   `json:"_key,omitempty" example:"123"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"` // conflicts with other trainings, which did not reject the event
+	// DroppedExceptions of an updated training are no longer contained in the calendar and have been removed
+	DroppedExceptions []Exception `json:"droppedExceptions,omitempty"`
+}
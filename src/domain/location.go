@@ -4,6 +4,7 @@ type Location struct {
 	Entity
 	Lat          float64           `json:"lat,omitempty" example:"53.55"`
 	Lng          float64           `json:"lng,omitempty" example:"9.99"`
+	Timezone     string            `json:"timezone,omitempty" example:"Europe/Berlin"` // IANA time zone, defaults to the configured one
 	City         string            `json:"city,omitempty" example:"Hamburg"`
 	Type         string            `json:"type,omitempty" example:"spot"` // spot, gym, parkour-gym, office, public-transport
 	Information  map[string]string `json:"information,omitempty"`
@@ -11,3 +12,15 @@ type Location struct {
 	Photos
 	Comments []Comment `json:"comments,omitempty"`
 }
+
+func (l *Location) GetDescriptions() Descriptions {
+	return l.Descriptions
+}
+
+func (l *Location) GetComments() []Comment {
+	return l.Comments
+}
+
+func (l *Location) SetComments(comments []Comment) {
+	l.Comments = comments
+}
@@ -8,4 +8,8 @@ type Occurrence struct {
 	Begin      int       `json:"begin,omitempty"`    // seconds
 	Duration   int       `json:"duration,omitempty"` // seconds
 	LocationId string    `json:"locationId,omitempty" example:"location/123"`
+	Cancelled  bool      `json:"cancelled,omitempty"`
+	Reason     string    `json:"reason,omitempty" example:"Ostermontag"`
+	Start      time.Time `json:"start,omitempty"` // RFC 3339 date-time with the UTC offset of the time zone
+	End        time.Time `json:"end,omitempty"`   // RFC 3339 date-time, Duration seconds after Start
 }
@@ -0,0 +1,8 @@
+package domain
+
+// OccurrenceDTO enriches Occurrence with the Location it takes place at and the coaches responsible for it
+type OccurrenceDTO struct {
+	Occurrence
+	Location *Location `json:"location,omitempty"`
+	Coaches  []User    `json:"coaches,omitempty"` // substitute coaches if assigned, the organisers otherwise
+}
@@ -0,0 +1,8 @@
+package domain
+
+// OccurrenceEdit cancels or moves a single occurrence of a training
+type OccurrenceEdit struct {
+	Begin  *int       `json:"begin,omitempty"` // seconds, selects one of several occurrences on the same day
+	Reason string     `json:"reason,omitempty" example:"Halle gesperrt"`
+	To     *Exception `json:"to,omitempty"` // new date, time or location when moving, unset fields are kept
+}
@@ -8,3 +8,15 @@ type Page struct {
 	Photos
 	Comments []Comment `json:"comments,omitempty"`
 }
+
+func (p *Page) GetDescriptions() Descriptions {
+	return p.Descriptions
+}
+
+func (p *Page) GetComments() []Comment {
+	return p.Comments
+}
+
+func (p *Page) SetComments(comments []Comment) {
+	p.Comments = comments
+}
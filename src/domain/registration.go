@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Registration is an edge from a user to a training, stating that the user wants to attend the occurrence on Date
+type Registration struct {
+	Key     string    `json:"_key,omitempty"`
+	From    string    `json:"_from,omitempty"`
+	To      string    `json:"_to,omitempty"`
+	Label   string    `json:"label,omitempty"`
+	Date    time.Time `json:"date"`    // RFC 3339 date of the occurrence
+	Created time.Time `json:"created"` // RFC 3339 date-time, determines the order of the waitlist
+}
+
+// RegistrationDTO enriches Registration with the User who registered
+type RegistrationDTO struct {
+	Registration
+	User *User `json:"user,omitempty"`
+}
@@ -0,0 +1,11 @@
+package domain
+
+// SearchQueryOptions carries the text to search for in the descriptions of several types of entities
+type SearchQueryOptions struct {
+	Text     string
+	Language string
+	Types    []string // user, training, location, page or event
+	Score    float64  // together with After the position of the last result of the previous page
+	After    string   // type and key of the last result of the previous page such as training/123, empty for the first page
+	Limit    int
+}
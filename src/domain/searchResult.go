@@ -0,0 +1,18 @@
+package domain
+
+// SearchResult is a user, training, location, page or event found by the global search
+type SearchResult struct {
+	Type    string  `json:"type" example:"training"` // user, training, location, page or event
+	Key     string  `json:"_key" example:"123"`
+	Title   string  `json:"title,omitempty" example:"Parkour im Park"`
+	Text    string  `json:"text,omitempty"`                                                    // description the snippet is taken from, not returned
+	Snippet string  `json:"snippet,omitempty" example:"Komm zum <mark>Parkour</mark> im Park"` // HTML-escaped excerpt with the matches marked
+	Score   float64 `json:"score" example:"2.5"`                                               // BM25 relevance
+}
+
+// SearchResults is a page of the results of the global search, sorted by relevance
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+	Facets  map[string]int `json:"facets"`         // number of all results per type
+	Next    string         `json:"next,omitempty"` // cursor of the following page, empty on the last page
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// StoredOccurrence is an Occurrence materialised in the occurrences collection, so that calendars do not need to
+// expand the cycles of every training on each request
+type StoredOccurrence struct {
+	Key string `json:"_key,omitempty"`
+	Occurrence
+	TrainingKey string `json:"trainingId" example:"123"`
+	LocationKey string `json:"resolvedLocationId,omitempty" example:"123"` // key of the location it takes place at, also when inherited from the training
+}
+
+// OccurrenceWindow is the time span the stored occurrences cover, which is empty until they have been stored once. It
+// is stored next to them so that every instance of the API knows it, also after a restart.
+type OccurrenceWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Covers tells whether the stored occurrences include the days between from (inclusive) and to (exclusive)
+func (w OccurrenceWindow) Covers(from, to time.Time) bool {
+	return !w.To.IsZero() && !from.Before(w.From) && !to.After(w.To)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOccurrenceWindow_Covers(t *testing.T) {
+	var w OccurrenceWindow
+	from := time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)
+	if w.Covers(from, from.AddDate(0, 0, 1)) {
+		t.Errorf("Covers() = true before the occurrences have been stored")
+	}
+	w = OccurrenceWindow{From: from, To: from.AddDate(0, 0, 365)}
+	if !w.Covers(from, from.AddDate(0, 0, 28)) {
+		t.Errorf("Covers() = false within the window")
+	}
+	if w.Covers(from.AddDate(0, 0, -1), from.AddDate(0, 0, 28)) || w.Covers(from, from.AddDate(0, 0, 366)) {
+		t.Errorf("Covers() = true beyond the window")
+	}
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Suggestion completes the text typed into a search box with the name of a user, the city of a location or a title
+type Suggestion struct {
+	Type string `json:"type" example:"location"` // user, training, location, page or event
+	Key  string `json:"_key" example:"123"`
+	Text string `json:"text" example:"Hamburg"`
+}
@@ -6,7 +6,23 @@ type Training struct {
 	Type         string            `json:"type,omitempty" example:"training"` // parkour-training, parkour-jam, meeting, show, competition, slackline, tour
 	Information  map[string]string `json:"information,omitempty"`
 	Descriptions Descriptions      `json:"descriptions,omitempty"`
+	Timezone     string            `json:"timezone,omitempty" example:"Europe/Berlin"` // IANA time zone, overrides the one of the location
+	Holidays     *HolidayPause     `json:"holidays,omitempty"`
+	Capacity     int               `json:"capacity,omitempty"` // places per occurrence, further registrations are waitlisted
 	Photos
-	Comments []Comment `json:"comments,omitempty"`
-	Cycles   []Cycle   `json:"cycles,omitempty"`
+	Comments   []Comment   `json:"comments,omitempty"`
+	Cycles     []Cycle     `json:"cycles,omitempty"`
+	Exceptions []Exception `json:"exceptions,omitempty"`
+}
+
+func (t *Training) GetDescriptions() Descriptions {
+	return t.Descriptions
+}
+
+func (t *Training) GetComments() []Comment {
+	return t.Comments
+}
+
+func (t *Training) SetComments(comments []Comment) {
+	t.Comments = comments
 }
@@ -4,7 +4,7 @@ package domain
 type TrainingDTO struct {
 	Training
 	Location      *Location `json:"location,omitempty"`
-	LocationKey   string    `json:"locationId,omitempty" example:"123"`
-	OrganiserKeys []string  `json:"organiserIds,omitempty" example:"123"`
+	LocationKey   string    `json:"locationKey,omitempty" example:"123"`
+	OrganiserKeys []string  `json:"organiserKeys,omitempty" example:"123"`
 	Organisers    []User    `json:"organisers,omitempty"`
 }
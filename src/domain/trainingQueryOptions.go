@@ -2,10 +2,14 @@ package domain
 
 // TrainingQueryOptions carries query options filtering the list of trainings or limiting the returned items or details
 type TrainingQueryOptions struct {
+	Key          string
 	City         string
 	Weekday      int
 	OrganiserKey string
 	LocationKey  string
+	Lat          float64 // Latitude
+	Lng          float64 // Longitude
+	MaxDistance  float64 // Maximum distance of the location in meters, 0 to disable
 	Type         string
 	Text         string
 	Language     string
@@ -0,0 +1,22 @@
+package domain
+
+// TrainingValidation is the outcome of validating the cycles and exceptions of a training
+type TrainingValidation struct {
+	Valid      bool                  `json:"valid"`
+	Cycles     []CycleValidation     `json:"cycles"`
+	Exceptions []ExceptionValidation `json:"exceptions"`
+	Holidays   string                `json:"holidays,omitempty"`  // why pausing during holidays is invalid
+	Conflicts  []Conflict            `json:"conflicts,omitempty"` // overlaps with other trainings at the same location, which do not make it invalid
+}
+
+// CycleValidation explains why a cycle is invalid, or describes when a valid cycle takes place in every supported
+// language
+type CycleValidation struct {
+	Error    string            `json:"error,omitempty"`
+	Schedule map[string]string `json:"schedule,omitempty" example:"{\"de\":\"Jeden Freitag, 18:00–20:00\"}"`
+}
+
+// ExceptionValidation explains why an exception is invalid
+type ExceptionValidation struct {
+	Error string `json:"error,omitempty"`
+}
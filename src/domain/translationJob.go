@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// TranslationJob is a queued automatic translation of the description of an entity from one language into another
+type TranslationJob struct {
+	Key        string     `json:"_key,omitempty" example:"123"`
+	Collection string     `json:"collection" example:"trainings"`
+	EntityKey  string     `json:"entityId" example:"123"`
+	Source     string     `json:"source" example:"de"`
+	Target     string     `json:"target" example:"en"`
+	Created    time.Time  `json:"created"`
+	Due        time.Time  `json:"due"`                  // when the next attempt is made
+	Attempts   int        `json:"attempts,omitempty"`   // failed attempts so far
+	Error      string     `json:"error,omitempty"`      // why the last attempt failed
+	Done       *time.Time `json:"done,omitempty"`       // when the translation has been stored or turned out to be unnecessary
+	Characters int        `json:"characters,omitempty"` // characters sent to the translation service
+}
@@ -10,3 +10,15 @@ type User struct {
 	Photos
 	Comments []Comment `json:"comments,omitempty"`
 }
+
+func (u *User) GetDescriptions() Descriptions {
+	return u.Descriptions
+}
+
+func (u *User) GetComments() []Comment {
+	return u.Comments
+}
+
+func (u *User) SetComments(comments []Comment) {
+	u.Comments = comments
+}
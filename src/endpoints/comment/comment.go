@@ -1,4 +1,4 @@
-package user
+package comment
 
 import (
 	"encoding/json"
@@ -9,7 +9,7 @@ import (
 	"pkv/api/src/repository/t"
 )
 
-func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+func (h *Handler[T]) AddComment(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
 	var item domain.Comment
 	decoder := json.NewDecoder(r.Body)
 	defer r.Body.Close()
@@ -24,14 +24,14 @@ func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request, urlParams h
 		return
 	}
 	key := urlParams.ByName("key")
-	if err = h.service.AddComment(key, item.Author, item.Title, item.Text, r.Context()); err != nil {
+	if err = h.service.Add(key, item.Author, item.Title, item.Text, r.Context()); err != nil {
 		api.Error(w, r, err, 400)
 		return
 	}
 	api.SuccessJson(w, r, nil)
 }
 
-func (h *Handler) EditComment(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+func (h *Handler[T]) EditComment(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
 	title := r.URL.Query().Get("title")
 	var item domain.Comment
 	decoder := json.NewDecoder(r.Body)
@@ -47,14 +47,14 @@ func (h *Handler) EditComment(w http.ResponseWriter, r *http.Request, urlParams
 		return
 	}
 	key := urlParams.ByName("key")
-	if err = h.service.EditComment(key, item.Author, title, item.Title, item.Text, r.Context()); err != nil {
+	if err = h.service.Edit(key, item.Author, title, item.Title, item.Text, r.Context()); err != nil {
 		api.Error(w, r, err, 400)
 		return
 	}
 	api.SuccessJson(w, r, nil)
 }
 
-func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+func (h *Handler[T]) DeleteComment(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
 	title := r.URL.Query().Get("title")
 	author := r.URL.Query().Get("author")
 	var err error
@@ -64,7 +64,7 @@ func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request, urlParam
 		return
 	}
 	key := urlParams.ByName("key")
-	if err = h.service.DeleteComment(key, author, title, r.Context()); err != nil {
+	if err = h.service.Delete(key, author, title, r.Context()); err != nil {
 		api.Error(w, r, err, 400)
 		return
 	}
@@ -0,0 +1,15 @@
+package comment
+
+import (
+	"pkv/api/src/repository/graph"
+	"pkv/api/src/service/comment"
+)
+
+type Handler[T graph.CommentEntity] struct {
+	db      *graph.Db
+	service *comment.Service[T]
+}
+
+func NewHandler[T graph.CommentEntity](db *graph.Db, service *comment.Service[T]) *Handler[T] {
+	return &Handler[T]{db: db, service: service}
+}
@@ -1,8 +1,10 @@
 package crud
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/julienschmidt/httprouter"
+	"log"
 	"net/http"
 	"pkv/api/src/api"
 	"pkv/api/src/repository/graph"
@@ -10,16 +12,48 @@ import (
 )
 
 type Handler[T graph.Entity] struct {
-	db *graph.Db
-	em graph.EntityManager[T]
+	db      *graph.Db
+	em      graph.EntityManager[T]
+	hooks   []Hook[T]
+	written []Hook[T]
 }
 
+// Hook is called with every item before it is created or updated, and rejects the item by returning an error
+type Hook[T graph.Entity] func(item T, ctx context.Context) error
+
 type KeyResponse struct {
-	Key string `json:"_key,omitempty" example:"123"`
+	Key      string   `json:"_key,omitempty" example:"123"`
+	Warnings []string `json:"warnings,omitempty"` // problems of the written item that did not fail the write
+}
+
+func NewHandler[T graph.Entity](db *graph.Db, em graph.EntityManager[T], hooks ...Hook[T]) *Handler[T] {
+	return &Handler[T]{db: db, em: em, hooks: hooks}
+}
+
+// OnWrite registers hooks that are called after an item has been created, updated or deleted, the latter only carrying
+// the key. Their errors are logged, as the item has already been written.
+func (h *Handler[T]) OnWrite(hooks ...Hook[T]) *Handler[T] {
+	h.written = append(h.written, hooks...)
+	return h
+}
+
+// runWrittenHooks calls the hooks registered by OnWrite
+func (h *Handler[T]) runWrittenHooks(item T, ctx context.Context) {
+	for _, hook := range h.written {
+		if err := hook(item, ctx); err != nil {
+			log.Printf("processing written item %s failed: %v", item.GetKey(), err)
+		}
+	}
 }
 
-func NewHandler[T graph.Entity](db *graph.Db, em graph.EntityManager[T]) *Handler[T] {
-	return &Handler[T]{db, em}
+// runHooks calls the hooks in order and stops at the first error
+func (h *Handler[T]) runHooks(item T, ctx context.Context) error {
+	for _, hook := range h.hooks {
+		if err := hook(item, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Create handles the creation of new entities.
@@ -36,12 +70,18 @@ func (h *Handler[T]) Create(w http.ResponseWriter, r *http.Request, urlParams ht
 		api.Error(w, r, t.Errorf("decoding request body failed: %w", err), 400)
 		return
 	}
+	options := api.RequestWriteOptions(r)
+	if err := h.runHooks(item, api.WithWriteOptions(r.Context(), options)); err != nil {
+		api.Error(w, r, t.Errorf("validating entity failed: %w", err), 400)
+		return
+	}
 	err = h.em.Create(item, r.Context())
 	if err != nil {
 		api.Error(w, r, t.Errorf("creating entity failed: %w", err), 400)
 		return
 	}
-	api.SuccessJson(w, r, KeyResponse{item.GetKey()})
+	h.runWrittenHooks(item, r.Context())
+	api.SuccessJson(w, r, KeyResponse{item.GetKey(), api.LocaliseAll(options.Warnings, api.Language(r))})
 }
 
 // Read handles the retrieval of entities.
@@ -72,12 +112,18 @@ func (h *Handler[T]) Update(w http.ResponseWriter, r *http.Request, urlParams ht
 		api.Error(w, r, err, 400)
 		return
 	}
+	options := api.RequestWriteOptions(r)
+	if err := h.runHooks(item, api.WithWriteOptions(r.Context(), options)); err != nil {
+		api.Error(w, r, t.Errorf("validating entity failed: %w", err), 400)
+		return
+	}
 	err = h.em.Update(item, r.Context())
 	if err != nil {
 		api.Error(w, r, t.Errorf("updating entity failed: %w", err), 400)
 		return
 	}
-	api.SuccessJson(w, r, KeyResponse{item.GetKey()})
+	h.runWrittenHooks(item, r.Context())
+	api.SuccessJson(w, r, KeyResponse{item.GetKey(), api.LocaliseAll(options.Warnings, api.Language(r))})
 }
 
 func (h *Handler[T]) PostBody(r *http.Request) (T, error) {
@@ -98,12 +144,13 @@ func (h *Handler[T]) Delete(w http.ResponseWriter, r *http.Request, urlParams ht
 		return
 	}
 	key := urlParams.ByName("key")
-	var item T
+	item := h.em.Constructor()
 	item.SetKey(key)
 	err = h.em.Delete(item, r.Context())
 	if err != nil {
 		api.Error(w, r, t.Errorf("deleting entity failed: %w", err), 400)
 		return
 	}
-	api.SuccessJson(w, r, KeyResponse{key})
+	h.runWrittenHooks(item, r.Context())
+	api.SuccessJson(w, r, KeyResponse{Key: key})
 }
@@ -4,14 +4,16 @@ import (
 	"pkv/api/src/domain"
 	"pkv/api/src/repository/graph"
 	"pkv/api/src/service/photo"
+	"pkv/api/src/service/translation"
 )
 
 type Handler struct {
 	db           *graph.Db
 	photoService *photo.Service
+	translations *translation.Service
 	em           graph.EntityManager[*domain.Location]
 }
 
-func NewHandler(db *graph.Db, photoService *photo.Service, em graph.EntityManager[*domain.Location]) *Handler {
-	return &Handler{db: db, photoService: photoService, em: em}
+func NewHandler(db *graph.Db, photoService *photo.Service, translations *translation.Service, em graph.EntityManager[*domain.Location]) *Handler {
+	return &Handler{db: db, photoService: photoService, translations: translations, em: em}
 }
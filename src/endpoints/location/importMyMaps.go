@@ -3,6 +3,7 @@ package location
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"pkv/api/src/domain"
@@ -88,16 +89,18 @@ func processFolder(d Document, folder Folder, documentName string, folderPath []
 	}
 }
 
+// processPlacemark maps a placemark to a location, which is not written. Writing it requires queueing its translations
+// as ImportPkOrgSpot does.
 func processPlacemark(d Document, p Placemark, folderPath []string) domain.Location {
 	coords := strings.Split(p.Point.Coordinates, ",")
 	lat, lng := parseFloat(coords[1]), parseFloat(coords[0])
 	placemarkDescription := domain.Descriptions{
 		"de": {
-			Title:  p.Name,
-			Text:   p.Description,
-			Render: description.Render([]byte(p.Description)),
+			Title: p.Name,
+			Text:  p.Description,
 		},
 	}
+	description.RenderAll(placemarkDescription, nil, context.Background())
 	folderPathStr := ""
 	if len(folderPath) > 0 {
 		folderPathStr = strings.Join(folderPath, ";")
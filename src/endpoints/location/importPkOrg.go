@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/julienschmidt/httprouter"
 	"io"
+	"log"
 	"net/http"
 	"pkv/api/src/api"
 	"pkv/api/src/domain"
@@ -85,6 +86,9 @@ func (h *Handler) ImportPkOrgSpot(w http.ResponseWriter, r *http.Request, urlPar
 		api.Error(w, r, t.Errorf("failed to create location for spot %s: %w", spotID, err), 500)
 		return
 	}
+	if err := h.translations.Enqueue("locations", location.Key, location.Descriptions, r.Context()); err != nil {
+		log.Printf("queueing translations of location %s failed: %v", location.Key, err)
+	}
 
 	api.SuccessJson(w, r, location.Key)
 }
@@ -190,11 +194,11 @@ func mapLocationFromPkOrgSpot(spot Spot) domain.Location {
 		},
 		Descriptions: domain.Descriptions{
 			"de": {
-				Title:  spot.Title,
-				Text:   spot.Description,
-				Render: description.Render([]byte(spot.Description)),
+				Title: spot.Title,
+				Text:  spot.Description,
 			},
 		},
 	}
+	description.RenderAll(location.Descriptions, nil, context.Background())
 	return location
 }
@@ -10,6 +10,7 @@ import (
 	"pkv/api/src/repository/dpv"
 	"pkv/api/src/repository/graph"
 	"pkv/api/src/service/photo"
+	"pkv/api/src/service/translation"
 	"reflect"
 	"testing"
 	"time"
@@ -36,7 +37,7 @@ func createHandler(t *testing.T) Handler {
 	}
 	photoService := photo.NewService()
 	handler := Handler{
-		db, photoService, db.Locations,
+		db, photoService, translation.NewService(db, nil), db.Locations,
 	}
 	return handler
 }
@@ -253,9 +254,10 @@ func Test_mapLocationFromPkOrgSpot(t *testing.T) {
 			Descriptions: domain.Descriptions{
 				"de": {
 					"Hello World",
-					"# Heading\n<b>Bold</b><script>hide me</script>",
-					"\n<h1 id=\"heading\">Heading</h1>\n\n<p><b>Bold</b></p>\n",
+					"# Hello World\n\n# Heading\n<b>Bold</b><script>hide me</script>",
+					"<h1 id=\"hello-world\">Hello World</h1>\n\n<h1 id=\"heading\">Heading</h1>\n\n<p><b>Bold</b></p>\n",
 					false,
+					"",
 				},
 			},
 		}},
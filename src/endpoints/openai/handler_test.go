@@ -18,8 +18,8 @@ func TestProxyChatCompletions_Localhost(t *testing.T) {
 	if err != nil {
 		t.Fatalf("could not initialise config instance: %v", err)
 	}
-	if err := tr.LoadDE(config); err != nil {
-		log.Printf("Could not load strings_de.ini: %v", err)
+	if err := tr.Load(config); err != nil {
+		log.Printf("Could not load message catalogues: %v", err)
 	}
 	// Start a test Gemini API server
 	geminiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -105,8 +105,8 @@ func TestProxyChatCompletions_NonLocalhost(t *testing.T) {
 	if err != nil {
 		t.Fatalf("could not initialise config instance: %v", err)
 	}
-	if err := tr.LoadDE(config); err != nil {
-		log.Printf("Could not load strings_de.ini: %v", err)
+	if err := tr.Load(config); err != nil {
+		log.Printf("Could not load message catalogues: %v", err)
 	}
 
 	config.Auth.GeminiUrl = "invalid://invalid?key="
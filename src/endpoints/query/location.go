@@ -43,7 +43,7 @@ func (h *Handler) GetLocations(w http.ResponseWriter, r *http.Request, urlParams
 		MaxDistance: maxDistance,
 		Type:        query.Get("type"),
 		Text:        query.Get("text"),
-		Language:    query.Get("language"),
+		Language:    api.DescriptionLanguage(r),
 		Include:     api.MakeSet(query.Get("include")),
 		Skip:        skip,
 		Limit:       limit,
@@ -54,6 +54,10 @@ func (h *Handler) GetLocations(w http.ResponseWriter, r *http.Request, urlParams
 		api.Error(w, r, t.Errorf("querying locations failed: %w", err), 400)
 		return
 	}
+	project := api.DescriptionProjection(w, r)
+	for _, location := range locations {
+		project(location.Descriptions)
+	}
 	api.SuccessJson(w, r, locations)
 }
 
@@ -64,5 +68,6 @@ func (h *Handler) GetLocation(w http.ResponseWriter, r *http.Request, urlParams
 		api.Error(w, r, t.Errorf("read request failed: %w", err), 400)
 		return
 	}
+	api.DescriptionProjection(w, r)(item.Descriptions)
 	api.SuccessJson(w, r, item)
 }
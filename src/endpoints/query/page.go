@@ -15,7 +15,10 @@ func (h *Handler) GetPages(w http.ResponseWriter, r *http.Request, urlParams htt
 		api.Error(w, r, t.Errorf("querying pages failed: %w", err), 400)
 		return
 	}
-
+	project := api.DescriptionProjection(w, r)
+	for _, page := range pages {
+		project(page.Descriptions)
+	}
 	api.SuccessJson(w, r, pages)
 }
 
@@ -26,5 +29,6 @@ func (h *Handler) GetPage(w http.ResponseWriter, r *http.Request, urlParams http
 		api.Error(w, r, t.Errorf("read request failed: %w", err), 400)
 		return
 	}
+	api.DescriptionProjection(w, r)(item.Descriptions)
 	api.SuccessJson(w, r, item)
 }
@@ -8,51 +8,76 @@ import (
 	"pkv/api/src/repository/t"
 )
 
-// GetTrainings handles the GET /api/trainings endpoint.
+// GetTrainings handles the GET /api/trainings endpoint. With include=events, the events matching the same filters
+// follow the trainings, those ending after the from query parameter, which defaults to today, and starting no later
+// than the optional to. Skip and limit then apply to trainings and events together.
 func (h *Handler) GetTrainings(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
-	query := r.URL.Query()
-	weekday, err := api.ParseInt(query.Get("weekday"))
+	queryOptions, err := api.ParseTrainingQueryOptions(r)
 	if err != nil {
-		api.Error(w, r, t.Errorf("invalid weekday: %w", err), 400)
+		api.Error(w, r, err, 400)
 		return
 	}
-	skip, err := api.ParseInt(query.Get("skip"))
+	_, withEvents := queryOptions.Include["events"]
+	skip, limit := queryOptions.Skip, queryOptions.Limit
+	if withEvents {
+		queryOptions.Skip, queryOptions.Limit = 0, 0
+	}
+
+	trainings, err := h.db.GetFilteredTrainings(queryOptions, r.Context())
 	if err != nil {
-		api.Error(w, r, t.Errorf("invalid skip: %w", err), 400)
+		api.Error(w, r, t.Errorf("querying trainings failed: %w", err), 400)
 		return
 	}
-	limit, err := api.ParseInt(query.Get("limit"))
-	if err != nil {
-		api.Error(w, r, t.Errorf("invalid limit: %w", err), 400)
+	project := api.DescriptionProjection(w, r)
+	for _, training := range trainings {
+		api.ProjectTraining(project, training)
+	}
+	if !withEvents {
+		api.SuccessJson(w, r, trainings)
 		return
 	}
-	queryOptions := domain.TrainingQueryOptions{
-		City:         query.Get("city"),
-		Weekday:      weekday,
-		OrganiserKey: query.Get("organiser"),
-		LocationKey:  query.Get("location"),
-		Type:         query.Get("type"),
-		Text:         query.Get("text"),
-		Language:     query.Get("language"),
-		Include:      api.MakeSet(query.Get("include")),
-		Skip:         skip,
-		Limit:        limit,
+	from, to, err := api.ParseEventTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
 	}
-
-	trainings, err := h.db.GetFilteredTrainings(queryOptions, r.Context())
+	events, err := h.db.GetFilteredEvents(queryOptions, from, to, r.Context())
 	if err != nil {
-		api.Error(w, r, t.Errorf("querying trainings failed: %w", err), 400)
+		api.Error(w, r, t.Errorf("querying events failed: %w", err), 400)
 		return
 	}
-	api.SuccessJson(w, r, trainings)
+	items := make([]any, 0, len(trainings)+len(events))
+	for _, training := range trainings {
+		items = append(items, training)
+	}
+	for _, event := range events {
+		api.ProjectEvent(project, event)
+		items = append(items, event)
+	}
+	items = items[min(skip, len(items)):]
+	if limit > 0 {
+		items = items[:min(limit, len(items))]
+	}
+	api.SuccessJson(w, r, items)
 }
 
+// GetTraining handles the GET /api/training/:key endpoint. The whole training is returned, its location and organisers
+// only if they are included.
 func (h *Handler) GetTraining(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
 	key := urlParams.ByName("key")
-	item, err := h.db.Trainings.Read(key, r.Context())
+	include := api.MakeSet(r.URL.Query().Get("include"))
+	for _, field := range []string{"photos", "comments", "cycles", "exceptions"} {
+		include[field] = struct{}{}
+	}
+	trainings, err := h.db.GetFilteredTrainings(domain.TrainingQueryOptions{Key: key, Include: include}, r.Context())
 	if err != nil {
 		api.Error(w, r, t.Errorf("read request failed: %w", err), 400)
 		return
 	}
-	api.SuccessJson(w, r, item)
+	if len(trainings) == 0 {
+		api.Error(w, r, t.Errorf("training %s not found", key), 404)
+		return
+	}
+	api.ProjectTraining(api.DescriptionProjection(w, r), trainings[0])
+	api.SuccessJson(w, r, trainings[0])
 }
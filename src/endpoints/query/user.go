@@ -36,6 +36,10 @@ func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request, urlParams htt
 		api.Error(w, r, t.Errorf("querying users failed: %w", err), 400)
 		return
 	}
+	project := api.DescriptionProjection(w, r)
+	for _, user := range users {
+		project(user.Descriptions)
+	}
 	api.SuccessJson(w, r, users)
 }
 
@@ -46,5 +50,6 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request, urlParams http
 		api.Error(w, r, t.Errorf("read request failed: %w", err), 400)
 		return
 	}
+	api.DescriptionProjection(w, r)(item.Descriptions)
 	api.SuccessJson(w, r, item)
 }
@@ -0,0 +1,13 @@
+package search
+
+import (
+	"pkv/api/src/service/search"
+)
+
+type Handler struct {
+	service *search.Service
+}
+
+func NewHandler(service *search.Service) *Handler {
+	return &Handler{service: service}
+}
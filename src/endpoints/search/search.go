@@ -0,0 +1,30 @@
+package search
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+	"strings"
+)
+
+// GetSearch handles the GET /api/search endpoint, searching the descriptions of the types listed in the types query
+// parameter, or of all types, for the text q
+func (h *Handler) GetSearch(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	query := r.URL.Query()
+	limit, err := api.ParseInt(query.Get("limit"))
+	if err != nil {
+		api.Error(w, r, t.Errorf("invalid limit: %w", err), 400)
+		return
+	}
+	var types []string
+	if query.Get("types") != "" {
+		types = strings.Split(query.Get("types"), ",")
+	}
+	results, err := h.service.Search(query.Get("q"), api.DescriptionLanguage(r), types, query.Get("cursor"), limit, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	api.SuccessJson(w, r, results)
+}
@@ -0,0 +1,26 @@
+package search
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+)
+
+// GetSuggestions handles the GET /api/autocomplete endpoint, completing the text q. The suggestions may be cached
+// for a minute, as they are requested on every keystroke.
+func (h *Handler) GetSuggestions(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	query := r.URL.Query()
+	limit, err := api.ParseInt(query.Get("limit"))
+	if err != nil {
+		api.Error(w, r, t.Errorf("invalid limit: %w", err), 400)
+		return
+	}
+	suggestions, err := h.service.Suggest(query.Get("q"), api.DescriptionLanguage(r), limit, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	api.SuccessJson(w, r, suggestions)
+}
@@ -0,0 +1,151 @@
+package training
+
+import (
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"pkv/api/src/service/training"
+	"time"
+)
+
+// PutAttendance handles the PUT /api/training/:key/occurrences/:date/attendance endpoint, with which organisers record
+// the checked-in users and the number of anonymous participants of an occurrence
+func (h *Handler) PutAttendance(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	item, err := h.service.ReadTraining(urlParams.ByName("key"), r.Context())
+	if err != nil {
+		api.Error(w, r, err, readStatus(err))
+		return
+	}
+	user, err := api.RequireOrganiser(item, r, h.db)
+	if err != nil {
+		api.Error(w, r, err, 403)
+		return
+	}
+	var list domain.AttendanceList
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := decoder.Decode(&list); err != nil {
+		api.Error(w, r, t.Errorf("decoding request body failed: %w", err), 400)
+		return
+	}
+	list.Date = date
+	list.RecordedBy = user
+	if err := h.service.RecordAttendance(item, &list, r.Context()); err != nil {
+		api.Error(w, r, t.Errorf("recording attendance failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, list)
+}
+
+// GetAttendance handles the GET /api/training/:key/occurrences/:date/attendance endpoint
+func (h *Handler) GetAttendance(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	item, err := h.service.ReadTraining(urlParams.ByName("key"), r.Context())
+	if err != nil {
+		api.Error(w, r, err, readStatus(err))
+		return
+	}
+	if _, err := api.RequireOrganiser(item, r, h.db); err != nil {
+		api.Error(w, r, err, 403)
+		return
+	}
+	list, err := h.service.ReadAttendance(item, date, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("reading attendance failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, list)
+}
+
+// GetAttendanceReport handles the GET /api/reports/attendance endpoint. It aggregates the attendance of a training, of
+// the trainings of an organiser or, for global administrators, of all trainings. The report is returned as CSV if the
+// format query parameter is csv.
+func (h *Handler) GetAttendanceReport(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	from, to, err := parseReportTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	trainings, err := h.reportedTrainings(r)
+	if err != nil {
+		api.Error(w, r, t.Errorf("cannot create attendance report: %w", err), 403)
+		return
+	}
+	query := r.URL.Query()
+	report, err := h.service.AttendanceReport(trainings, from, to, query.Get("language"), r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("creating attendance report failed: %w", err), 400)
+		return
+	}
+	if query.Get("format") != "csv" {
+		api.SuccessJson(w, r, report)
+		return
+	}
+	csv, err := training.ReportCSV(report)
+	if err != nil {
+		api.Error(w, r, t.Errorf("exporting attendance report failed: %w", err), 500)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"attendance-"+from.Format(time.DateOnly)+".csv\"")
+	api.Success(w, r, []byte(csv))
+}
+
+// reportedTrainings reads the trainings selected by the training or organiser query parameter, provided the current
+// user may see their attendance
+func (h *Handler) reportedTrainings(r *http.Request) ([]domain.TrainingDTO, error) {
+	query := r.URL.Query()
+	if key := query.Get("training"); key != "" {
+		item, err := h.service.ReadTraining(key, r.Context())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := api.RequireOrganiser(item, r, h.db); err != nil {
+			return nil, err
+		}
+		return []domain.TrainingDTO{item}, nil
+	}
+	if organiser := query.Get("organiser"); organiser != "" {
+		if _, _, err := api.RequireUserAdmin(organiser, r, h.db); err != nil {
+			return nil, err
+		}
+		return h.service.FilterTrainings(domain.TrainingQueryOptions{OrganiserKey: organiser}, r.Context())
+	}
+	if _, err := api.RequireGlobalAdmin(r, h.db); err != nil {
+		return nil, err
+	}
+	return h.service.FilterTrainings(domain.TrainingQueryOptions{}, r.Context())
+}
+
+// parseReportTimespan reads the from and to query parameters, both being inclusive dates. It defaults to the current
+// year up to today and returns the end of the time span as an exclusive value.
+func parseReportTimespan(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	from, err := api.ParseDate(query.Get("from"))
+	if err != nil {
+		return from, from, t.Errorf("invalid from: %w", err)
+	}
+	to, err := api.ParseDate(query.Get("to"))
+	if err != nil {
+		return from, to, t.Errorf("invalid to: %w", err)
+	}
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if from.IsZero() {
+		from = time.Date(to.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
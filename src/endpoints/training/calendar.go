@@ -0,0 +1,29 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+)
+
+// GetCalendar handles the GET /api/calendar endpoint. It accepts the filters of GET /api/training as well as from and
+// to, skip and limit paginate the occurrences.
+func (h *Handler) GetCalendar(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	from, to, err := parseTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	options, err := api.ParseTrainingQueryOptions(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	entries, err := h.service.CalendarEntries(options, from, to, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("computing calendar failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, entries)
+}
@@ -0,0 +1,53 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+)
+
+// PutCoaches handles the PUT /api/training/:key/occurrences/:date/coaches endpoint. The request body lists the keys of
+// the users who coach the occurrence instead of the organisers, an empty list makes the organisers responsible again.
+func (h *Handler) PutCoaches(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	item, ok := h.readOrganisedTraining(w, r, urlParams)
+	if !ok {
+		return
+	}
+	var userKeys []string
+	if !decode(w, r, &userKeys) {
+		return
+	}
+	coaches, err := h.service.AssignCoaches(item, date, userKeys, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("cannot assign coaches: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, coaches)
+}
+
+// GetDuties handles the GET /api/user/:key/duties endpoint, listing the occurrences the user coaches between the from
+// and to query parameters
+func (h *Handler) GetDuties(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	key, _, err := api.RequireUserAdmin(urlParams.ByName("key"), r, h.db)
+	if err != nil {
+		api.Error(w, r, err, 403)
+		return
+	}
+	from, to, err := parseTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	duties, err := h.service.Duties(key, from, to, r.URL.Query().Get("language"), r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("listing coaching duties failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, duties)
+}
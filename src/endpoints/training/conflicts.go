@@ -0,0 +1,28 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+)
+
+// GetConflicts handles the GET /api/admin/conflicts endpoint, listing the overlapping occurrences of trainings at the
+// same location between the from and to query parameters
+func (h *Handler) GetConflicts(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	if _, err := api.RequireGlobalAdmin(r, h.db); err != nil {
+		api.Error(w, r, t.Errorf("cannot list conflicts: %w", err), 403)
+		return
+	}
+	from, to, err := parseTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	conflicts, err := h.service.Conflicts(from, to, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("checking for conflicts failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, conflicts)
+}
@@ -0,0 +1,68 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+)
+
+// GetEvents handles the GET /api/event endpoint. It accepts the filters of GET /api/training and lists the events
+// ending after the from query parameter, which defaults to today, and starting no later than the optional to.
+func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	options, err := api.ParseTrainingQueryOptions(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	from, to, err := api.ParseEventTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	events, err := h.db.GetFilteredEvents(options, from, to, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("querying events failed: %w", err), 400)
+		return
+	}
+	project := api.DescriptionProjection(w, r)
+	for _, event := range events {
+		api.ProjectEvent(project, event)
+	}
+	api.SuccessJson(w, r, events)
+}
+
+// GetEvent handles the GET /api/event/:key endpoint, returning the event with its locations and organisers
+func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	event, err := h.service.ReadEvent(urlParams.ByName("key"), r.Context())
+	if err != nil {
+		api.Error(w, r, err, 404)
+		return
+	}
+	api.ProjectEvent(api.DescriptionProjection(w, r), event)
+	api.SuccessJson(w, r, event)
+}
+
+// PutEventLocations handles the PUT /api/admin/event/:key/locations endpoint. The request body lists the keys of the
+// locations the event takes place at, replacing the previous ones.
+func (h *Handler) PutEventLocations(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	if _, err := api.RequireGlobalAdmin(r, h.db); err != nil {
+		api.Error(w, r, t.Errorf("cannot set locations of event: %w", err), 403)
+		return
+	}
+	var locationKeys []string
+	if !decode(w, r, &locationKeys) {
+		return
+	}
+	key := urlParams.ByName("key")
+	if err := h.service.SetEventLocations(key, locationKeys, r.Context()); err != nil {
+		api.Error(w, r, t.Errorf("cannot set locations of event: %w", err), 400)
+		return
+	}
+	event, err := h.service.ReadEvent(key, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	api.SuccessJson(w, r, event)
+}
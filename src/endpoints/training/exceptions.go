@@ -0,0 +1,112 @@
+package training
+
+import (
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// CancelOccurrence handles the POST /api/training/:key/occurrences/:date/cancel endpoint. It responds with the
+// occurrences on that day, including the cancelled ones.
+func (h *Handler) CancelOccurrence(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	item, ok := h.readOrganisedTraining(w, r, urlParams)
+	if !ok {
+		return
+	}
+	var edit domain.OccurrenceEdit
+	if !decode(w, r, &edit) {
+		return
+	}
+	if err := h.service.CancelOccurrence(item, date, edit, r.Context()); err != nil {
+		api.Error(w, r, t.Errorf("cancelling occurrence failed: %w", err), 400)
+		return
+	}
+	h.respondWithDay(w, r, item.Key, date)
+}
+
+// MoveOccurrence handles the POST /api/training/:key/occurrences/:date/move endpoint. It responds with the occurrences
+// on the day the occurrence has been moved to.
+func (h *Handler) MoveOccurrence(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	item, ok := h.readOrganisedTraining(w, r, urlParams)
+	if !ok {
+		return
+	}
+	var edit domain.OccurrenceEdit
+	if !decode(w, r, &edit) {
+		return
+	}
+	if err := h.service.MoveOccurrence(item, date, edit, r.Context()); err != nil {
+		api.Error(w, r, t.Errorf("moving occurrence failed: %w", err), 400)
+		return
+	}
+	if !edit.To.Date.IsZero() {
+		date = edit.To.Date
+	}
+	h.respondWithDay(w, r, item.Key, date)
+}
+
+// AddOccurrence handles the POST /api/training/:key/occurrences endpoint, adding an extra date to the training. It
+// responds with the occurrences on that day.
+func (h *Handler) AddOccurrence(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	item, ok := h.readOrganisedTraining(w, r, urlParams)
+	if !ok {
+		return
+	}
+	var occurrence domain.Exception
+	if !decode(w, r, &occurrence) {
+		return
+	}
+	if err := h.service.AddOccurrence(item, occurrence, r.Context()); err != nil {
+		api.Error(w, r, t.Errorf("adding occurrence failed: %w", err), 400)
+		return
+	}
+	h.respondWithDay(w, r, item.Key, occurrence.Date)
+}
+
+// readOrganisedTraining reads the training of the key parameter if the current user is allowed to organise it
+func (h *Handler) readOrganisedTraining(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) (domain.TrainingDTO, bool) {
+	item, err := h.service.ReadTraining(urlParams.ByName("key"), r.Context())
+	if err != nil {
+		api.Error(w, r, err, readStatus(err))
+		return item, false
+	}
+	if _, err := api.RequireOrganiser(item, r, h.db); err != nil {
+		api.Error(w, r, err, 403)
+		return item, false
+	}
+	return item, true
+}
+
+func (h *Handler) respondWithDay(w http.ResponseWriter, r *http.Request, key string, date time.Time) {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	occurrences, err := h.service.Occurrences(key, date, date.AddDate(0, 0, 1), true, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("computing occurrences failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, occurrences)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, item interface{}) bool {
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := decoder.Decode(item); err != nil {
+		api.Error(w, r, t.Errorf("decoding request body failed: %w", err), 400)
+		return false
+	}
+	return true
+}
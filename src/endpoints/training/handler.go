@@ -0,0 +1,24 @@
+package training
+
+import (
+	"errors"
+	"pkv/api/src/repository/graph"
+	"pkv/api/src/service/training"
+)
+
+type Handler struct {
+	db      *graph.Db
+	service *training.Service
+}
+
+func NewHandler(db *graph.Db, service *training.Service) *Handler {
+	return &Handler{db: db, service: service}
+}
+
+// readStatus returns the status code answering a failed ReadTraining, 404 only if the training does not exist
+func readStatus(err error) int {
+	if errors.Is(err, training.ErrNotFound) {
+		return 404
+	}
+	return 500
+}
@@ -0,0 +1,24 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+)
+
+// GetHolidays handles the GET /api/holidays/:state endpoint, listing public and school holidays between the from and
+// to query parameters.
+func (h *Handler) GetHolidays(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	from, to, err := parseTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	holidays, err := h.service.Holidays(urlParams.ByName("state"), from, to)
+	if err != nil {
+		api.Error(w, r, t.Errorf("listing holidays failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, holidays)
+}
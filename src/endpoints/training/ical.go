@@ -0,0 +1,90 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/ical"
+	"pkv/api/src/repository/t"
+	"strings"
+	"time"
+)
+
+// CalendarSuffix is the file extension of iCalendar feeds
+const CalendarSuffix = ".ics"
+
+// GetTrainingCalendar handles the GET /api/training/:key.ics endpoint.
+func (h *Handler) GetTrainingCalendar(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	key := strings.TrimSuffix(urlParams.ByName("key"), CalendarSuffix)
+	training, err := h.service.ReadTraining(key, r.Context())
+	if err != nil {
+		api.Error(w, r, err, readStatus(err))
+		return
+	}
+	h.writeCalendar(w, r, "", []domain.TrainingDTO{training}, nil, r.URL.Query().Get("language"))
+}
+
+// GetEventCalendar handles the GET /api/event/:key.ics endpoint.
+func (h *Handler) GetEventCalendar(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	key := strings.TrimSuffix(urlParams.ByName("key"), CalendarSuffix)
+	event, err := h.service.ReadEvent(key, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 404)
+		return
+	}
+	h.writeCalendar(w, r, "", nil, []domain.EventDTO{event}, r.URL.Query().Get("language"))
+}
+
+// GetOrganiserCalendar handles the GET /api/user/:key/trainings.ics endpoint.
+func (h *Handler) GetOrganiserCalendar(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	key := urlParams.ByName("key")
+	user, err := h.db.Users.Read(key, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("read request failed: %w", err), 404)
+		return
+	}
+	language := r.URL.Query().Get("language")
+	trainings, err := h.service.FilterTrainings(domain.TrainingQueryOptions{OrganiserKey: key}, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	events, err := h.service.FilterEvents(domain.TrainingQueryOptions{OrganiserKey: key}, time.Time{}, time.Time{}, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	h.writeCalendar(w, r, user.Name, trainings, events, language)
+}
+
+// GetTrainingsCalendar handles the GET /api/training.ics endpoint, it accepts the same filters as GET /api/training and
+// also contains the events matching them.
+func (h *Handler) GetTrainingsCalendar(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	options, err := api.ParseTrainingQueryOptions(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	trainings, err := h.service.FilterTrainings(options, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	events, err := h.service.FilterEvents(options, time.Time{}, time.Time{}, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	h.writeCalendar(w, r, options.City, trainings, events, options.Language)
+}
+
+func (h *Handler) writeCalendar(w http.ResponseWriter, r *http.Request, name string, trainings []domain.TrainingDTO, events []domain.EventDTO, language string) {
+	feed, err := h.service.Calendar(name, trainings, events, language, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("creating calendar failed: %w", err), 400)
+		return
+	}
+	w.Header().Set("Content-Type", ical.ContentType)
+	api.Success(w, r, feed.Bytes())
+}
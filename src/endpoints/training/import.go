@@ -0,0 +1,38 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"io"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+)
+
+// MaxCalendarSize limits the size of uploaded iCalendar files
+const MaxCalendarSize = 5 << 20 // 5MB
+
+// ImportCalendar handles the POST /api/trainings/import/ical endpoint. The request body is an iCalendar file.
+func (h *Handler) ImportCalendar(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	query := r.URL.Query()
+	organiser, _, err := api.RequireUserAdmin(query.Get("organiser"), r, h.db)
+	if err != nil {
+		api.Error(w, r, t.Errorf("cannot import trainings: %w", err), 403)
+		return
+	}
+	language := query.Get("language")
+	if language == "" {
+		language = "de"
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxCalendarSize))
+	if err != nil {
+		api.Error(w, r, t.Errorf("failed to read request body: %w", err), 400)
+		return
+	}
+	ctx := api.WithWriteOptions(r.Context(), api.RequestWriteOptions(r))
+	report, err := h.service.ImportCalendar(data, organiser, query.Get("location"), language, api.Language(r), ctx)
+	if err != nil {
+		api.Error(w, r, t.Errorf("importing calendar failed: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, report)
+}
@@ -0,0 +1,52 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// GetOccurrences handles the GET /api/training/:key/occurrences endpoint. Cancelled occurrences are included if the
+// cancelled query parameter is true.
+func (h *Handler) GetOccurrences(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	from, to, err := parseTimespan(r)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	training, err := h.service.ReadTraining(urlParams.ByName("key"), r.Context())
+	if err != nil {
+		api.Error(w, r, err, readStatus(err))
+		return
+	}
+	cancelled := r.URL.Query().Get("cancelled") == "true"
+	occurrences, err := h.service.OccurrencesOf(training, from, to, cancelled, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("computing occurrences failed: %w", err), 500)
+		return
+	}
+	api.SuccessJson(w, r, occurrences)
+}
+
+// parseTimespan reads the from and to query parameters, both being inclusive dates. It defaults to the next four weeks
+// and returns the end of the time span as an exclusive value.
+func parseTimespan(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	from, err := api.ParseDate(query.Get("from"))
+	if err != nil {
+		return from, from, t.Errorf("invalid from: %w", err)
+	}
+	to, err := api.ParseDate(query.Get("to"))
+	if err != nil {
+		return from, to, t.Errorf("invalid to: %w", err)
+	}
+	if from.IsZero() {
+		from = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 27)
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
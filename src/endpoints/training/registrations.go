@@ -0,0 +1,83 @@
+package training
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// Register handles the POST /api/training/:key/occurrences/:date/rsvp endpoint. The current user attends the
+// occurrence, or is waitlisted if it is fully booked.
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	user, err := api.Authenticated(r)
+	if err != nil {
+		api.Error(w, r, t.Errorf("authentication failed: %w", err), 401)
+		return
+	}
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	attendee, err := h.service.Register(urlParams.ByName("key"), user, date, r.Context())
+	if err != nil {
+		api.Error(w, r, t.Errorf("cannot register for occurrence: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, attendee)
+}
+
+// Unregister handles the DELETE /api/training/:key/occurrences/:date/rsvp endpoint. The first user on the waitlist
+// moves up.
+func (h *Handler) Unregister(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	user, err := api.Authenticated(r)
+	if err != nil {
+		api.Error(w, r, t.Errorf("authentication failed: %w", err), 401)
+		return
+	}
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	if err := h.service.Unregister(urlParams.ByName("key"), user, date, r.Context()); err != nil {
+		api.Error(w, r, t.Errorf("cannot withdraw registration: %w", err), 400)
+		return
+	}
+	api.SuccessJson(w, r, struct{}{})
+}
+
+// GetAttendees handles the GET /api/training/:key/occurrences/:date/attendees endpoint, which only the organisers of
+// the training may access
+func (h *Handler) GetAttendees(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	date, err := parseOccurrenceDate(urlParams)
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	training, err := h.service.ReadTraining(urlParams.ByName("key"), r.Context())
+	if err != nil {
+		api.Error(w, r, err, readStatus(err))
+		return
+	}
+	if _, err := api.RequireOrganiser(training, r, h.db); err != nil {
+		api.Error(w, r, err, 403)
+		return
+	}
+	attendance, err := h.service.Attendance(training, date, r.Context())
+	if err != nil {
+		api.Error(w, r, err, 400)
+		return
+	}
+	api.SuccessJson(w, r, attendance)
+}
+
+func parseOccurrenceDate(urlParams httprouter.Params) (time.Time, error) {
+	date, err := api.ParseDate(urlParams.ByName("date"))
+	if err != nil || date.IsZero() {
+		return date, t.Errorf("invalid date %s, expected YYYY-MM-DD", urlParams.ByName("date"))
+	}
+	return date, nil
+}
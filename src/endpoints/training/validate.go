@@ -0,0 +1,35 @@
+package training
+
+import (
+	"encoding/json"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"pkv/api/src/api"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"pkv/api/src/service/training"
+)
+
+// ValidateTraining handles the POST /api/trainings/validate endpoint. The request body is a training, whose cycles
+// and exceptions are checked without storing anything. Valid cycles are described in German and English, and a valid
+// training is checked for conflicts with other trainings at the same location, which is given by the location query
+// parameter for trainings that have not been linked to their location yet.
+func (h *Handler) ValidateTraining(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+	var item domain.Training
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := decoder.Decode(&item); err != nil {
+		api.Error(w, r, t.Errorf("decoding request body failed: %w", err), 400)
+		return
+	}
+	validation := training.ValidateTraining(item, api.Language(r))
+	if validation.Valid {
+		conflicts, err := h.service.ConflictsOf(item, r.URL.Query().Get("location"), r.Context())
+		if err != nil {
+			api.Error(w, r, t.Errorf("checking for conflicts failed: %w", err), 400)
+			return
+		}
+		validation.Conflicts = conflicts
+	}
+	api.SuccessJson(w, r, validation)
+}
@@ -2,33 +2,37 @@ package calendar
 
 import (
 	"pkv/api/src/domain"
+	"slices"
 	"sort"
 	"time"
 )
 
-// ComputeDays computes occurrences within the specified date range.
+// ComputeDays computes occurrences within the specified date range. Days are civil dates, they are returned as
+// midnight in the location of start, no matter which location the dates of the cycle have been stored in.
 func ComputeDays(cycle domain.Cycle, start, end time.Time) []time.Time {
-	if cycle.Interval == 0 {
-		cycle.Interval = 1
-	}
+	cycle = normalise(cycle, start.Location())
 
 	var occurrences []time.Time
 
-	// Initialize the current date to the first satisfying date on or after the start date.
-	current := fixWeekday(cycle, cycle.Startdate)
-	for current.Before(start) {
-		current = ComputeNextSatisfyingDate(cycle, current)
-	}
-
-	// Compute occurrences within the specified date range.
-	for current.Before(end) && current.Before(cycle.Enddate) {
+	// Compute occurrences within the specified date range. A cycle without an end date continues forever.
+	days := newIterator(cycle, start)
+	for current := days.next(start); current.Before(end) && (cycle.Enddate.IsZero() || current.Before(cycle.Enddate)); current = days.next(current) {
 		occurrences = append(occurrences, current)
-		current = ComputeNextSatisfyingDate(cycle, current)
 	}
 
 	return occurrences
 }
 
+// FirstDay returns the first day of the cycle on or after the given date, and false if there is none
+func FirstDay(cycle domain.Cycle, from time.Time) (time.Time, bool) {
+	cycle = normalise(cycle, from.Location())
+	day := newIterator(cycle, from).next(from)
+	if day.Equal(never) || (!cycle.Enddate.IsZero() && !day.Before(cycle.Enddate)) {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
 // GenerateOccurrences is a convenience function for ComputeDays. ComputeDays did
 // compute a list of time.Time values with the same location as the start
 // parameter and the hour, min, sec set to zero. However, cycle contains the
@@ -62,7 +66,7 @@ nextOccurrence:
 	for _, occurrence := range occurrences {
 		// Skip if an exception exists for the current occurrence.
 		for _, exception := range exceptions {
-			if exception.Date.Equal(occurrence.Date) {
+			if civil(exception.Date, start.Location()).Equal(occurrence.Date) {
 				continue nextOccurrence
 			}
 		}
@@ -70,6 +74,7 @@ nextOccurrence:
 	}
 	// Add the exceptions that are not already in the list of occurrences.
 	for _, exception := range exceptions {
+		exception.Date = civil(exception.Date, start.Location())
 		if exception.Date.Before(start) || exception.Date.After(end) {
 			continue
 		}
@@ -83,21 +88,74 @@ nextOccurrence:
 			Begin:      exception.Begin,
 			Duration:   exception.Duration,
 			LocationId: exception.LocationId,
+			Reason:     exception.Reason,
 		})
 	}
 	// finally we need to sort the occurrences by date and by begin:
-	sort.Slice(newOccurrences, func(i, j int) bool {
-		if newOccurrences[i].Date.Equal(newOccurrences[j].Date) {
-			return newOccurrences[i].Begin < newOccurrences[j].Begin
+	SortOccurrences(newOccurrences)
+	return newOccurrences
+}
+
+// SortOccurrences sorts occurrences by date and by begin
+func SortOccurrences(occurrences []domain.Occurrence) {
+	sort.Slice(occurrences, func(i, j int) bool {
+		if occurrences[i].Date.Equal(occurrences[j].Date) {
+			return occurrences[i].Begin < occurrences[j].Begin
 		}
-		return newOccurrences[i].Date.Before(newOccurrences[j].Date)
+		return occurrences[i].Date.Before(occurrences[j].Date)
 	})
-	return newOccurrences
 }
 
-// TrimOccurrences helps to clean up the list of occurrences stored in a database.
+// ComputeOccurrences combines ComputeDays, GenerateOccurrences and ApplyExceptions
+// for all cycles of a training. It returns the sorted list of occurrences on the
+// days between start (inclusive) and end (exclusive), with Start and End being the
+// instants at which they take place in the given time zone.
+func ComputeOccurrences(cycles []domain.Cycle, exceptions []domain.Exception, start, end time.Time, zone *time.Location) []domain.Occurrence {
+	var occurrences []domain.Occurrence
+	for _, cycle := range cycles {
+		occurrences = append(occurrences, GenerateOccurrences(cycle, ComputeDays(cycle, start, end))...)
+	}
+	// ApplyExceptions treats the end as inclusive, whereas ComputeDays does not.
+	occurrences = ApplyExceptions(occurrences, exceptions, start, end.Add(-time.Nanosecond))
+	for i := range occurrences {
+		occurrences[i] = Localise(occurrences[i], zone)
+	}
+	return occurrences
+}
 
-func trimOccurrences(occurrences []domain.Occurrence, start, end time.Time) []domain.Occurrence {
+// CancelledOccurrences returns the occurrences of the cycles between start (inclusive) and end (exclusive) that are
+// cancelled by an exception without replacement on the same day. They are marked as cancelled, along with the reason
+// given by the exception.
+func CancelledOccurrences(cycles []domain.Cycle, exceptions []domain.Exception, start, end time.Time, zone *time.Location) []domain.Occurrence {
+	reasons := make(map[time.Time]string)
+	replaced := make(map[time.Time]bool)
+	for _, exception := range exceptions {
+		date := civil(exception.Date, start.Location())
+		if exception.Duration > 0 {
+			replaced[date] = true
+		} else if reasons[date] == "" {
+			reasons[date] = exception.Reason
+		}
+	}
+	var cancelled []domain.Occurrence
+	for _, cycle := range cycles {
+		for _, occurrence := range GenerateOccurrences(cycle, ComputeDays(cycle, start, end)) {
+			reason, ok := reasons[occurrence.Date]
+			if !ok || replaced[occurrence.Date] {
+				continue
+			}
+			occurrence.Cancelled = true
+			occurrence.Reason = reason
+			cancelled = append(cancelled, Localise(occurrence, zone))
+		}
+	}
+	SortOccurrences(cancelled)
+	return cancelled
+}
+
+// TrimOccurrences helps to clean up the list of occurrences stored in a database. It keeps the occurrences between start
+// and end, both inclusive.
+func TrimOccurrences(occurrences []domain.Occurrence, start, end time.Time) []domain.Occurrence {
 	var newOccurrences []domain.Occurrence
 	for _, occurrence := range occurrences {
 		if occurrence.Date.Before(start) || occurrence.Date.After(end) {
@@ -108,41 +166,208 @@ func trimOccurrences(occurrences []domain.Occurrence, start, end time.Time) []do
 	return newOccurrences
 }
 
-func fixWeekday(cycle domain.Cycle, current time.Time) time.Time {
-	if cycle.Monthday == 0 && cycle.Weekday != 0 {
-		daysToNextWeekday := (cycle.Weekday - int(current.Weekday()) + 7) % 7
-		current = current.AddDate(0, 0, daysToNextWeekday)
-	} else if cycle.Monthday != 0 {
-		dayInMonth := calculateDayInMonth(current.Year(), current.Month(), cycle.Monthday, cycle.Weekday)
-		if dayInMonth < current.Day() {
-			dayInMonth = calculateDayInMonth(current.Year(), current.Month()+1, cycle.Monthday, cycle.Weekday)
-			current = time.Date(current.Year(), current.Month()+1, dayInMonth, 0, 0, 0, 0, current.Location())
-		} else {
-			current = time.Date(current.Year(), current.Month(), dayInMonth, 0, 0, 0, 0, current.Location())
+// ComputeNextSatisfyingDate returns the day of the cycle following the given day, ignoring the end date
+func ComputeNextSatisfyingDate(cycle domain.Cycle, current time.Time) time.Time {
+	cycle = normalise(cycle, current.Location())
+	days := newIterator(cycle, current)
+	return days.next(current.AddDate(0, 0, 1))
+}
+
+// normalise treats an interval of zero as one and moves the dates of the cycle to the given location
+func normalise(cycle domain.Cycle, loc *time.Location) domain.Cycle {
+	if cycle.Interval == 0 {
+		cycle.Interval = 1
+	}
+	cycle.Startdate = civil(cycle.Startdate, loc)
+	if !cycle.Enddate.IsZero() {
+		cycle.Enddate = civil(cycle.Enddate, loc)
+	}
+	return cycle
+}
+
+// maxSkippedPeriods limits the search for a month or year that has the day of a cycle, such as the 31st, a fifth
+// Friday or the 29th of February
+const maxSkippedPeriods = 48
+
+// never is returned as the next date of a cycle whose day does not exist in any of the following periods
+var never = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// iterator walks through the days of a cycle period by period, a period being a day, a week, a month or a year
+type iterator struct {
+	cycle  domain.Cycle
+	unit   unit
+	period time.Time   // first day of the current period
+	days   []time.Time // days of the current period that have not been returned yet
+	done   bool
+}
+
+// newIterator starts at the period containing the given date, or at the first period of the cycle if it is later.
+// The periods are counted starting with the one of the first occurrence on or after the start date of the cycle.
+func newIterator(cycle domain.Cycle, from time.Time) *iterator {
+	it := &iterator{cycle: cycle, unit: unitOf(cycle)}
+	it.period = it.unit.start(cycle.Startdate)
+	for i := 0; ; i++ {
+		if i > maxSkippedPeriods {
+			it.done = true
+			return it
+		}
+		it.days = daysOf(cycle, it.unit, it.period)
+		if len(it.days) > 0 && !it.days[len(it.days)-1].Before(cycle.Startdate) {
+			break
 		}
+		it.period = it.unit.add(it.period, 1)
+	}
+	// Skip whole intervals at once.
+	if it.period.Before(from) {
+		periods := it.unit.between(it.period, from)
+		it.period = it.unit.add(it.period, periods/cycle.Interval*cycle.Interval)
+		it.days = daysOf(cycle, it.unit, it.period)
 	}
-	return current
+	return it
 }
 
-func ComputeNextSatisfyingDate(cycle domain.Cycle, current time.Time) time.Time {
-	if cycle.Monthday == 0 && cycle.Weekday == 0 {
-		// Advance by Interval days.
-		current = current.AddDate(0, 0, cycle.Interval)
-	} else if cycle.Monthday == 0 {
-		// Advance by Interval weeks.
-		current = current.AddDate(0, 0, 7*cycle.Interval)
-	} else {
-		// Advance by Interval months.
-		nextMonth := time.Date(current.Year(), current.Month()+time.Month(cycle.Interval), 1, 0, 0, 0, 0, current.Location())
+// next returns the first day of the cycle on or after the given date, which must not be before the previous one
+func (it *iterator) next(from time.Time) time.Time {
+	skipped := 0
+	for !it.done {
+		for len(it.days) > 0 {
+			day := it.days[0]
+			it.days = it.days[1:]
+			if !day.Before(from) && !day.Before(it.cycle.Startdate) {
+				return day
+			}
+		}
+		it.period = it.unit.add(it.period, it.cycle.Interval)
+		it.days = daysOf(it.cycle, it.unit, it.period)
+		if len(it.days) > 0 {
+			skipped = 0
+		} else if skipped++; skipped > maxSkippedPeriods {
+			it.done = true
+		}
+	}
+	return never
+}
+
+// unit is the length of the periods a cycle repeats in
+type unit int
+
+const (
+	daily unit = iota
+	weekly
+	monthly
+	yearly
+)
+
+func unitOf(cycle domain.Cycle) unit {
+	switch {
+	case cycle.Month != 0:
+		return yearly
+	case cycle.Monthday != 0:
+		return monthly
+	case len(Weekdays(cycle)) > 0:
+		return weekly
+	default:
+		return daily
+	}
+}
+
+// start returns the first day of the period containing the date, weeks starting on Monday
+func (u unit) start(date time.Time) time.Time {
+	switch u {
+	case weekly:
+		return date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	case monthly:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	case yearly:
+		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+	default:
+		return date
+	}
+}
 
-		// Calculate the day within the month based on Monthday and Weekday.
-		dayInMonth := calculateDayInMonth(nextMonth.Year(), nextMonth.Month(), cycle.Monthday, cycle.Weekday)
+func (u unit) add(period time.Time, n int) time.Time {
+	switch u {
+	case weekly:
+		return period.AddDate(0, 0, 7*n)
+	case monthly:
+		return period.AddDate(0, n, 0)
+	case yearly:
+		return period.AddDate(n, 0, 0)
+	default:
+		return period.AddDate(0, 0, n)
+	}
+}
+
+// between returns the number of whole periods from one period to the one containing the given date
+func (u unit) between(period, date time.Time) int {
+	switch u {
+	case weekly:
+		return daysBetween(period, date) / 7
+	case monthly:
+		return (date.Year()-period.Year())*12 + int(date.Month()-period.Month())
+	case yearly:
+		return date.Year() - period.Year()
+	default:
+		return daysBetween(period, date)
+	}
+}
+
+// daysBetween counts the civil days between two dates, regardless of DST transitions in between. Day numbers are
+// taken from Unix seconds, as durations between dates more than 292 years apart overflow.
+func daysBetween(from, to time.Time) int {
+	utcFrom := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	utcTo := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(utcTo.Unix()/86400 - utcFrom.Unix()/86400)
+}
+
+// daysOf returns the sorted days of the cycle within the period starting at the given day
+func daysOf(cycle domain.Cycle, u unit, period time.Time) []time.Time {
+	weekdays := Weekdays(cycle)
+	switch u {
+	case daily:
+		return []time.Time{period}
+	case weekly:
+		days := make([]time.Time, 0, len(weekdays))
+		for _, weekday := range weekdays {
+			days = append(days, period.AddDate(0, 0, weekday-1))
+		}
+		return days
+	case yearly:
+		period = time.Date(period.Year(), time.Month(cycle.Month), 1, 0, 0, 0, 0, period.Location())
+		if cycle.Monthday == 0 {
+			return nil
+		}
+	}
+	if len(weekdays) == 0 {
+		weekdays = []int{0}
+	}
+	var days []time.Time
+	for _, weekday := range weekdays {
+		if dayInMonth, ok := validDayInMonth(period.Year(), period.Month(), cycle.Monthday, weekday); ok {
+			days = append(days, time.Date(period.Year(), period.Month(), dayInMonth, 0, 0, 0, 0, period.Location()))
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
 
-		// Update the current date.
-		current = time.Date(nextMonth.Year(), nextMonth.Month(), dayInMonth, 0, 0, 0, 0, current.Location())
+// Weekdays returns the sorted set of weekdays of a cycle combining Weekday and Weekdays, or none if it takes place on
+// any day
+func Weekdays(cycle domain.Cycle) []int {
+	var weekdays []int
+	for weekday := 1; weekday <= 7; weekday++ {
+		if weekday == cycle.Weekday || slices.Contains(cycle.Weekdays, weekday) {
+			weekdays = append(weekdays, weekday)
+		}
 	}
+	return weekdays
+}
 
-	return current
+// validDayInMonth calls calculateDayInMonth and reports whether the month has such a day
+func validDayInMonth(year int, month time.Month, monthday, weekday int) (int, bool) {
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	dayInMonth := calculateDayInMonth(year, month, monthday, weekday)
+	return dayInMonth, dayInMonth >= 1 && dayInMonth <= daysInMonth
 }
 
 func calculateDayInMonth(year int, month time.Month, monthday, weekday int) int {
@@ -157,6 +382,8 @@ func calculateDayInMonth(year int, month time.Month, monthday, weekday int) int
 		// Count days from the beginning of the month.
 		dayInMonth := monthday
 		if weekday != 0 {
+			// stays 0 if the month has no such weekday
+			dayInMonth = 0
 			weekdayCount := monthday
 			for i := 1; i <= daysInMonth; i++ {
 				currentDay := firstOfMonth.AddDate(0, 0, i-1).Weekday()
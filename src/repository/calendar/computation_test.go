@@ -28,6 +28,8 @@ func Test_calculateDayInMonth(t *testing.T) {
 		{"last Tuesday of January", 2023, time.January, -1, 2, 31},
 		{"penultimate Saturday of January", 2023, time.January, -2, 6, 21},
 		{"penultimate Sunday of January", 2023, time.January, -2, 7, 22},
+		{"fifth Monday of February", 2023, time.February, 5, 1, 0},
+		{"fifth last Monday of February", 2023, time.February, -5, 1, 0},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -40,6 +42,28 @@ func Test_calculateDayInMonth(t *testing.T) {
 	}
 }
 
+func Test_daysBetween(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		want int
+	}{
+		{"same day", time.Date(2023, 3, 1, 23, 0, 0, 0, time.UTC), time.Date(2023, 3, 1, 1, 0, 0, 0, time.UTC), 0},
+		{"across DST", time.Date(2023, 3, 25, 0, 0, 0, 0, berlin), time.Date(2023, 3, 27, 0, 0, 0, 0, berlin), 2},
+		{"backwards", time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), -1},
+		{"from year one", time.Time{}, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), 738579},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysBetween(tt.from, tt.to); got != tt.want {
+				t.Errorf("daysBetween(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestComputeDays(t *testing.T) {
 	currentDate := time.Now()
 	jan1 := time.Date(2023, 1, 1, 0, 0, 0, 0, currentDate.Location())
@@ -56,7 +80,7 @@ func TestComputeDays(t *testing.T) {
 		want  []time.Time
 	}{
 		{"every day",
-			domain.Cycle{0, 0, 0, 1800, 900, jan1, dec31, ""},
+			domain.Cycle{Begin: 1800, Duration: 900, Startdate: jan1, Enddate: dec31},
 			mar1, mar5,
 			[]time.Time{
 				time.Date(2023, 3, 1, 0, 0, 0, 0, currentDate.Location()),
@@ -66,7 +90,7 @@ func TestComputeDays(t *testing.T) {
 			},
 		},
 		{"every day, short period",
-			domain.Cycle{0, 0, 0, 1800, 900, mar1, mar5, ""},
+			domain.Cycle{Begin: 1800, Duration: 900, Startdate: mar1, Enddate: mar5},
 			jan1, dec31,
 			[]time.Time{
 				time.Date(2023, 3, 1, 0, 0, 0, 0, currentDate.Location()),
@@ -76,7 +100,7 @@ func TestComputeDays(t *testing.T) {
 			},
 		},
 		{"every Friday",
-			domain.Cycle{5, 0, 0, 1800, 900, jan1, dec31, ""},
+			domain.Cycle{Weekday: 5, Begin: 1800, Duration: 900, Startdate: jan1, Enddate: dec31},
 			mar1, mar31,
 			[]time.Time{
 				time.Date(2023, 3, 3, 0, 0, 0, 0, currentDate.Location()),
@@ -86,7 +110,7 @@ func TestComputeDays(t *testing.T) {
 			},
 		},
 		{"every Friday, short period",
-			domain.Cycle{5, 0, 0, 1800, 900, mar1, mar31, ""},
+			domain.Cycle{Weekday: 5, Begin: 1800, Duration: 900, Startdate: mar1, Enddate: mar31},
 			jan1, dec31,
 			[]time.Time{
 				time.Date(2023, 3, 3, 0, 0, 0, 0, currentDate.Location()),
@@ -96,7 +120,7 @@ func TestComputeDays(t *testing.T) {
 			},
 		},
 		{"every second Sunday in every second month",
-			domain.Cycle{7, 2, 2, 1800, 900, jan1, dec31, ""},
+			domain.Cycle{Weekday: 7, Monthday: 2, Interval: 2, Begin: 1800, Duration: 900, Startdate: jan1, Enddate: dec31},
 			mar1, dec31,
 			[]time.Time{
 				time.Date(2023, 3, 12, 0, 0, 0, 0, currentDate.Location()),
@@ -107,7 +131,7 @@ func TestComputeDays(t *testing.T) {
 			},
 		},
 		{"every second Sunday in every second month, short period",
-			domain.Cycle{7, 2, 2, 1800, 900, mar1, dec31, ""},
+			domain.Cycle{Weekday: 7, Monthday: 2, Interval: 2, Begin: 1800, Duration: 900, Startdate: mar1, Enddate: dec31},
 			jan1, dec31,
 			[]time.Time{
 				time.Date(2023, 3, 12, 0, 0, 0, 0, currentDate.Location()),
@@ -128,6 +152,42 @@ func TestComputeDays(t *testing.T) {
 	}
 }
 
+func TestComputeDaysWithoutStartdate(t *testing.T) {
+	mar1 := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	mar18 := time.Date(2023, 3, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		cycle domain.Cycle
+		want  []time.Time
+	}{
+		{"every Friday",
+			domain.Cycle{Weekday: 5},
+			[]time.Time{
+				time.Date(2023, 3, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 10, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 17, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{"every fifth day",
+			domain.Cycle{Interval: 5},
+			[]time.Time{
+				time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 17, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeDays(tt.cycle, mar1, mar18)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeDays(%#v, %v, %v)\n  got = %v,\n  want  %v", tt.cycle, mar1, mar18, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGenerateOccurrences(t *testing.T) {
 	currentDate := time.Now()
 	jan1 := time.Date(2023, 1, 1, 0, 0, 0, 0, currentDate.Location())
@@ -139,7 +199,7 @@ func TestGenerateOccurrences(t *testing.T) {
 		want  []domain.Occurrence
 	}{
 		{"test",
-			domain.Cycle{0, 0, 0, 1800, 900, jan1, dec31, ""},
+			domain.Cycle{Begin: 1800, Duration: 900, Startdate: jan1, Enddate: dec31},
 			[]time.Time{
 				time.Date(2023, 3, 1, 0, 0, 0, 0, currentDate.Location()),
 				time.Date(2023, 3, 2, 0, 0, 0, 0, currentDate.Location()),
@@ -147,10 +207,10 @@ func TestGenerateOccurrences(t *testing.T) {
 				time.Date(2023, 3, 4, 0, 0, 0, 0, currentDate.Location()),
 			},
 			[]domain.Occurrence{
-				{time.Date(2023, 3, 1, 0, 0, 0, 0, currentDate.Location()), 1800, 900, ""},
-				{time.Date(2023, 3, 2, 0, 0, 0, 0, currentDate.Location()), 1800, 900, ""},
-				{time.Date(2023, 3, 3, 0, 0, 0, 0, currentDate.Location()), 1800, 900, ""},
-				{time.Date(2023, 3, 4, 0, 0, 0, 0, currentDate.Location()), 1800, 900, ""},
+				{Date: time.Date(2023, 3, 1, 0, 0, 0, 0, currentDate.Location()), Begin: 1800, Duration: 900},
+				{Date: time.Date(2023, 3, 2, 0, 0, 0, 0, currentDate.Location()), Begin: 1800, Duration: 900},
+				{Date: time.Date(2023, 3, 3, 0, 0, 0, 0, currentDate.Location()), Begin: 1800, Duration: 900},
+				{Date: time.Date(2023, 3, 4, 0, 0, 0, 0, currentDate.Location()), Begin: 1800, Duration: 900},
 			},
 		},
 	}
@@ -182,81 +242,81 @@ func TestApplyExceptions(t *testing.T) {
 	}{
 		{"exception adds location",
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 			[]domain.Exception{
-				{mar2, 1800, 900, "123"},
+				{Date: mar2, Begin: 1800, Duration: 900, LocationId: "123"},
 			},
 			mar1, mar31,
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, "123"},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900, LocationId: "123"},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 		},
 		{"two events are cancelled",
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 			[]domain.Exception{
-				{mar2, 0, 0, ""},
-				{mar5, 0, 0, ""},
+				{Date: mar2},
+				{Date: mar5},
 			},
 			mar1, mar31,
-			[]domain.Occurrence{{mar1, 1800, 900, ""}},
+			[]domain.Occurrence{{Date: mar1, Begin: 1800, Duration: 900}},
 		},
 		{"exception adds one more day",
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 			[]domain.Exception{
-				{mar2, 1800, 900, ""},
+				{Date: mar2, Begin: 1800, Duration: 900},
 			},
 			mar1, mar31,
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 		},
 		{"exception is before or after timespan",
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 			[]domain.Exception{
-				{jan1, 1800, 900, "123"},
-				{dec31, 1800, 900, "123"},
+				{Date: jan1, Begin: 1800, Duration: 900, LocationId: "123"},
+				{Date: dec31, Begin: 1800, Duration: 900, LocationId: "123"},
 			},
 			mar1, mar31,
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 		},
 		{"exception reschedules one day into two events on the same day",
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 			[]domain.Exception{
-				{mar2, 2400, 450, ""},
-				{mar2, 3600, 450, ""},
+				{Date: mar2, Begin: 2400, Duration: 450},
+				{Date: mar2, Begin: 3600, Duration: 450},
 			},
 			mar1, mar31,
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 2400, 450, ""},
-				{mar2, 3600, 450, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 2400, Duration: 450},
+				{Date: mar2, Begin: 3600, Duration: 450},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 		},
 	}
@@ -270,6 +330,73 @@ func TestApplyExceptions(t *testing.T) {
 	}
 }
 
+func TestComputeOccurrences(t *testing.T) {
+	currentDate := time.Now()
+	jan1 := time.Date(2023, 1, 1, 0, 0, 0, 0, currentDate.Location())
+	mar1 := time.Date(2023, 3, 1, 0, 0, 0, 0, currentDate.Location())
+	mar3 := time.Date(2023, 3, 3, 0, 0, 0, 0, currentDate.Location())
+	mar4 := time.Date(2023, 3, 4, 0, 0, 0, 0, currentDate.Location())
+	mar6 := time.Date(2023, 3, 6, 0, 0, 0, 0, currentDate.Location())
+	mar10 := time.Date(2023, 3, 10, 0, 0, 0, 0, currentDate.Location())
+	mar13 := time.Date(2023, 3, 13, 0, 0, 0, 0, currentDate.Location())
+	mar15 := time.Date(2023, 3, 15, 0, 0, 0, 0, currentDate.Location())
+	mondays := domain.Cycle{Weekday: 1, Begin: 64800, Duration: 7200, Startdate: jan1}
+	fridays := domain.Cycle{Weekday: 5, Begin: 68400, Duration: 5400, Startdate: jan1, LocationId: "location/123"}
+	tests := []struct {
+		name       string
+		cycles     []domain.Cycle
+		exceptions []domain.Exception
+		start      time.Time
+		end        time.Time
+		want       []domain.Occurrence
+	}{
+		{"two cycles without end date are merged",
+			[]domain.Cycle{fridays, mondays},
+			nil,
+			mar1, mar15,
+			[]domain.Occurrence{
+				{Date: mar3, Begin: 68400, Duration: 5400, LocationId: "location/123"},
+				{Date: mar6, Begin: 64800, Duration: 7200},
+				{Date: mar10, Begin: 68400, Duration: 5400, LocationId: "location/123"},
+				{Date: mar13, Begin: 64800, Duration: 7200},
+			},
+		},
+		{"exceptions cancel and add occurrences",
+			[]domain.Cycle{fridays, mondays},
+			[]domain.Exception{
+				{Date: mar3},
+				{Date: mar4, Begin: 36000, Duration: 3600},
+			},
+			mar1, mar10,
+			[]domain.Occurrence{
+				{Date: mar4, Begin: 36000, Duration: 3600},
+				{Date: mar6, Begin: 64800, Duration: 7200},
+			},
+		},
+		{"exception on the end date is excluded",
+			[]domain.Cycle{mondays},
+			[]domain.Exception{
+				{Date: mar10, Begin: 36000, Duration: 3600},
+			},
+			mar1, mar10,
+			[]domain.Occurrence{
+				{Date: mar6, Begin: 64800, Duration: 7200},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeOccurrences(tt.cycles, tt.exceptions, tt.start, tt.end, time.UTC)
+			for i := range tt.want {
+				tt.want[i] = Localise(tt.want[i], time.UTC)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeOccurrences(%v, %v, %v, %v)\n  got = %v,\n  want  %v", tt.cycles, tt.exceptions, tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTrimOccurrences(t *testing.T) {
 	currentDate := time.Now()
 	mar1 := time.Date(2023, 3, 1, 0, 0, 0, 0, currentDate.Location())
@@ -285,34 +412,177 @@ func TestTrimOccurrences(t *testing.T) {
 	}{
 		{"trim one day",
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 			mar2, mar31,
 			[]domain.Occurrence{
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 		},
 		{"trim one day, short period",
 			[]domain.Occurrence{
-				{mar1, 1800, 900, ""},
-				{mar2, 1800, 900, ""},
-				{mar5, 1800, 900, ""},
+				{Date: mar1, Begin: 1800, Duration: 900},
+				{Date: mar2, Begin: 1800, Duration: 900},
+				{Date: mar5, Begin: 1800, Duration: 900},
 			},
 			mar2, mar2,
 			[]domain.Occurrence{
-				{mar2, 1800, 900, ""},
+				{Date: mar2, Begin: 1800, Duration: 900},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := trimOccurrences(tt.occurrences, tt.start, tt.end)
+			got := TrimOccurrences(tt.occurrences, tt.start, tt.end)
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("trimOccurrences(%v, %v, %v)\n  got = %v,\n  want  %v", tt.occurrences, tt.start, tt.end, got, tt.want)
+				t.Errorf("TrimOccurrences(%v, %v, %v)\n  got = %v,\n  want  %v", tt.occurrences, tt.start, tt.end, got, tt.want)
 			}
 		})
 	}
 }
+
+func TestComputeDaysSkipsMonthsWithoutTheDay(t *testing.T) {
+	jan1 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan1next := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		cycle domain.Cycle
+		want  []time.Time
+	}{
+		{"every fifth Sunday",
+			domain.Cycle{Weekday: 7, Monthday: 5, Startdate: jan1},
+			[]time.Time{
+				time.Date(2023, 1, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 7, 30, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 10, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{"every 31st in every second month",
+			domain.Cycle{Monthday: 31, Interval: 2, Startdate: jan1},
+			[]time.Time{
+				time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 5, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeDays(tt.cycle, jan1, jan1next)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeDays(%#v, %v, %v)\n  got = %v,\n  want  %v", tt.cycle, jan1, jan1next, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeDaysWithRicherRules(t *testing.T) {
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name  string
+		cycle domain.Cycle
+		start time.Time
+		end   time.Time
+		want  []time.Time
+	}{
+		{"every Monday and Thursday",
+			domain.Cycle{Weekdays: []int{4, 1}, Startdate: day(2024, 1, 1)},
+			day(2024, 3, 1), day(2024, 3, 12),
+			[]time.Time{day(2024, 3, 4), day(2024, 3, 7), day(2024, 3, 11)},
+		},
+		{"Weekday and Weekdays are combined",
+			domain.Cycle{Weekday: 1, Weekdays: []int{4}, Startdate: day(2024, 1, 1)},
+			day(2024, 3, 1), day(2024, 3, 12),
+			[]time.Time{day(2024, 3, 4), day(2024, 3, 7), day(2024, 3, 11)},
+		},
+		{"Monday and Thursday every other week, counting from the week of the first occurrence",
+			domain.Cycle{Weekdays: []int{1, 4}, Interval: 2, Startdate: day(2024, 3, 6)},
+			day(2024, 3, 1), day(2024, 4, 1),
+			[]time.Time{day(2024, 3, 7), day(2024, 3, 18), day(2024, 3, 21)},
+		},
+		{"first Monday and Thursday of the month",
+			domain.Cycle{Weekdays: []int{1, 4}, Monthday: 1, Startdate: day(2024, 1, 1)},
+			day(2024, 2, 1), day(2024, 4, 1),
+			[]time.Time{day(2024, 2, 1), day(2024, 2, 5), day(2024, 3, 4), day(2024, 3, 7)},
+		},
+		{"last day of the month",
+			domain.Cycle{Monthday: -1, Startdate: day(2024, 1, 1)},
+			day(2024, 1, 1), day(2024, 5, 1),
+			[]time.Time{day(2024, 1, 31), day(2024, 2, 29), day(2024, 3, 31), day(2024, 4, 30)},
+		},
+		{"annual jam on the first Saturday of June",
+			domain.Cycle{Weekday: 6, Monthday: 1, Month: 6, Startdate: day(2023, 7, 1)},
+			day(2023, 1, 1), day(2027, 1, 1),
+			[]time.Time{day(2024, 6, 1), day(2025, 6, 7), day(2026, 6, 6)},
+		},
+		{"every other year on the last day of February",
+			domain.Cycle{Monthday: -1, Month: 2, Interval: 2, Startdate: day(2024, 1, 1)},
+			day(2024, 1, 1), day(2030, 1, 1),
+			[]time.Time{day(2024, 2, 29), day(2026, 2, 28), day(2028, 2, 29)},
+		},
+		{"29th of February only in leap years",
+			domain.Cycle{Monthday: 29, Month: 2, Startdate: day(2023, 1, 1)},
+			day(2023, 1, 1), day(2033, 1, 1),
+			[]time.Time{day(2024, 2, 29), day(2028, 2, 29), day(2032, 2, 29)},
+		},
+		{"yearly cycle without start date",
+			domain.Cycle{Monthday: 24, Month: 12},
+			day(2024, 1, 1), day(2026, 1, 1),
+			[]time.Time{day(2024, 12, 24), day(2025, 12, 24)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeDays(tt.cycle, tt.start, tt.end)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeDays(%#v, %v, %v)\n  got = %v,\n  want  %v", tt.cycle, tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstDay(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if got, ok := FirstDay(domain.Cycle{Monthday: 29, Month: 2}, from); !ok || !got.Equal(time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("FirstDay() = %v, %v, want 2028-02-29", got, ok)
+	}
+	if _, ok := FirstDay(domain.Cycle{Monthday: 30, Month: 2}, from); ok {
+		t.Errorf("FirstDay() found the 30th of February")
+	}
+	ended := domain.Cycle{Weekday: 1, Enddate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}
+	if _, ok := FirstDay(ended, from); ok {
+		t.Errorf("FirstDay() found a day after the end date")
+	}
+}
+
+func TestCancelledOccurrences(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC)
+	}
+	mondays := []domain.Cycle{{Weekday: 1, Begin: 64800, Duration: 3600}}
+	exceptions := []domain.Exception{
+		{Date: day(1), Reason: "Ostermontag"},
+		{Date: day(8)},
+		{Date: day(15)},
+		{Date: day(15), Begin: 36000, Duration: 3600},
+	}
+	got := CancelledOccurrences(mondays, exceptions, day(1), day(30), time.UTC)
+	want := []domain.Occurrence{
+		{Date: day(1), Begin: 64800, Duration: 3600, Cancelled: true, Reason: "Ostermontag"},
+		{Date: day(8), Begin: 64800, Duration: 3600, Cancelled: true},
+	}
+	for i := range want {
+		want[i] = Localise(want[i], time.UTC)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CancelledOccurrences()\n  got = %+v,\n  want  %+v", got, want)
+	}
+}
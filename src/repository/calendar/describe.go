@@ -0,0 +1,212 @@
+package calendar
+
+import (
+	"fmt"
+	"pkv/api/src/domain"
+	"time"
+)
+
+// ScheduleLanguages are the languages DescribeCycle can generate texts in
+var ScheduleLanguages = []string{"de", "en"}
+
+var weekdaysDE = []string{"Tag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag", "Sonntag"}
+var weekdaysEN = []string{"day", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+var monthsDE = []string{"", "Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+
+var ordinalsDE = []string{"", "ersten", "zweiten", "dritten", "vierten", "fünften"}
+var lastOrdinalsDE = []string{"", "letzten", "vorletzten", "drittletzten", "viertletzten", "fünftletzten"}
+var ordinalsEN = []string{"", "first", "second", "third", "fourth", "fifth"}
+var lastOrdinalsEN = []string{"", "last", "second-to-last", "third-to-last", "fourth-to-last", "fifth-to-last"}
+
+// DescribeCycle returns a human-readable text of when a cycle takes place, such as "Jeden ersten Donnerstag im Monat,
+// 18:00–20:00". Languages other than German fall back to English. The cycle is expected to be valid.
+func DescribeCycle(cycle domain.Cycle, language string) string {
+	var text string
+	if language == "de" {
+		text = describeRuleDE(cycle)
+	} else {
+		text = describeRuleEN(cycle)
+	}
+	return text + ", " + describeTime(cycle)
+}
+
+func describeRuleDE(cycle domain.Cycle) string {
+	weekdays := Weekdays(cycle)
+	weekday := joinWeekdays(weekdays, weekdaysDE, "und")
+	if cycle.Month != 0 {
+		return describeYearDE(cycle, weekday)
+	}
+	switch {
+	case cycle.Monthday == 0 && cycle.Interval <= 1:
+		return "Jeden " + weekday
+	case cycle.Monthday == 0 && cycle.Interval == 2:
+		return "Jeden zweiten " + weekday
+	case cycle.Monthday == 0 && len(weekdays) == 0:
+		return fmt.Sprintf("Alle %d Tage", cycle.Interval)
+	case cycle.Monthday == 0:
+		return fmt.Sprintf("Alle %d Wochen am %s", cycle.Interval, weekday)
+	}
+	var day string
+	if len(weekdays) == 0 {
+		day = "Am " + dayOfMonthDE(cycle.Monthday) + " Tag"
+	} else {
+		day = "jeden " + ordinal(cycle.Monthday, ordinalsDE, lastOrdinalsDE) + " " + weekday
+	}
+	switch {
+	case len(weekdays) == 0 && cycle.Interval <= 1:
+		return day + " jedes Monats"
+	case len(weekdays) == 0 && cycle.Interval == 2:
+		return day + " jedes zweiten Monats"
+	case len(weekdays) == 0:
+		return fmt.Sprintf("%s alle %d Monate", day, cycle.Interval)
+	case cycle.Interval <= 1:
+		return "J" + day[1:] + " im Monat"
+	case cycle.Interval == 2:
+		return "Alle zwei Monate " + day
+	default:
+		return fmt.Sprintf("Alle %d Monate %s", cycle.Interval, day)
+	}
+}
+
+func describeRuleEN(cycle domain.Cycle) string {
+	weekdays := Weekdays(cycle)
+	weekday := joinWeekdays(weekdays, weekdaysEN, "and")
+	if cycle.Month != 0 {
+		return describeYearEN(cycle, weekday)
+	}
+	switch {
+	case cycle.Monthday == 0 && cycle.Interval <= 1:
+		return "Every " + weekday
+	case cycle.Monthday == 0 && cycle.Interval == 2:
+		return "Every other " + weekday
+	case cycle.Monthday == 0 && len(weekdays) == 0:
+		return fmt.Sprintf("Every %d days", cycle.Interval)
+	case cycle.Monthday == 0:
+		return fmt.Sprintf("Every %d weeks on %s", cycle.Interval, weekday)
+	}
+	day := dayEN(cycle, weekday)
+	switch {
+	case cycle.Interval <= 1:
+		return "On " + day + " of every month"
+	case cycle.Interval == 2:
+		return "On " + day + " of every other month"
+	default:
+		return fmt.Sprintf("On %s of every %d months", day, cycle.Interval)
+	}
+}
+
+func describeYearDE(cycle domain.Cycle, weekday string) string {
+	month := monthsDE[cycle.Month]
+	var day string
+	switch {
+	case len(Weekdays(cycle)) > 0:
+		day = "am " + ordinal(cycle.Monthday, ordinalsDE, lastOrdinalsDE) + " " + weekday + " im " + month
+	case cycle.Monthday > 0:
+		day = fmt.Sprintf("am %d. %s", cycle.Monthday, month)
+	default:
+		day = "am " + dayOfMonthDE(cycle.Monthday) + " Tag im " + month
+	}
+	switch {
+	case cycle.Interval <= 1:
+		return "Jedes Jahr " + day
+	case cycle.Interval == 2:
+		return "Alle zwei Jahre " + day
+	default:
+		return fmt.Sprintf("Alle %d Jahre %s", cycle.Interval, day)
+	}
+}
+
+func describeYearEN(cycle domain.Cycle, weekday string) string {
+	day := dayEN(cycle, weekday) + " of " + time.Month(cycle.Month).String()
+	switch {
+	case cycle.Interval <= 1:
+		return "Every year on " + day
+	case cycle.Interval == 2:
+		return "Every other year on " + day
+	default:
+		return fmt.Sprintf("Every %d years on %s", cycle.Interval, day)
+	}
+}
+
+// dayEN names the day within a month, such as "the first Monday" or "the 3rd day"
+func dayEN(cycle domain.Cycle, weekday string) string {
+	if len(Weekdays(cycle)) == 0 {
+		return "the " + dayOfMonthEN(cycle.Monthday) + " day"
+	}
+	return "the " + ordinal(cycle.Monthday, ordinalsEN, lastOrdinalsEN) + " " + weekday
+}
+
+// joinWeekdays lists the names of the weekdays, such as "Monday, Wednesday and Friday", or names any day
+func joinWeekdays(weekdays []int, names []string, and string) string {
+	if len(weekdays) == 0 {
+		return names[0]
+	}
+	text := names[weekdays[0]]
+	for i, weekday := range weekdays[1:] {
+		if i == len(weekdays)-2 {
+			text += " " + and + " "
+		} else {
+			text += ", "
+		}
+		text += names[weekday]
+	}
+	return text
+}
+
+// ordinal picks the word for the n-th or the n-th last day
+func ordinal(monthday int, ordinals []string, lastOrdinals []string) string {
+	if monthday < 0 && -monthday < len(lastOrdinals) {
+		return lastOrdinals[-monthday]
+	}
+	if monthday > 0 && monthday < len(ordinals) {
+		return ordinals[monthday]
+	}
+	return fmt.Sprint(monthday)
+}
+
+func dayOfMonthDE(monthday int) string {
+	switch {
+	case monthday > 0:
+		return fmt.Sprintf("%d.", monthday)
+	case -monthday < len(lastOrdinalsDE):
+		return lastOrdinalsDE[-monthday]
+	default:
+		return fmt.Sprintf("%d.-letzten", -monthday)
+	}
+}
+
+func dayOfMonthEN(monthday int) string {
+	switch {
+	case -monthday > 0 && -monthday < len(lastOrdinalsEN):
+		return lastOrdinalsEN[-monthday]
+	case monthday < 0:
+		return dayOfMonthEN(-monthday) + "-to-last"
+	}
+	suffix := "th"
+	switch {
+	case monthday%100 >= 11 && monthday%100 <= 13:
+	case monthday%10 == 1:
+		suffix = "st"
+	case monthday%10 == 2:
+		suffix = "nd"
+	case monthday%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", monthday, suffix)
+}
+
+// describeTime returns the wall clock time of the beginning and the end, e.g. 18:00–20:00
+func describeTime(cycle domain.Cycle) string {
+	begin := Clock(cycle.Begin)
+	if cycle.Duration == 0 {
+		return begin
+	}
+	return begin + "–" + Clock(cycle.Begin+cycle.Duration)
+}
+
+// Clock formats seconds since midnight as wall clock time, e.g. 18:00
+func Clock(seconds int) string {
+	seconds %= secondsPerDay
+	return fmt.Sprintf("%02d:%02d", seconds/3600, seconds%3600/60)
+}
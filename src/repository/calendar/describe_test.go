@@ -0,0 +1,94 @@
+package calendar
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func TestDescribeCycle(t *testing.T) {
+	evening := domain.Cycle{Begin: 18 * 3600, Duration: 2 * 3600}
+	with := func(weekday, monthday, interval int) domain.Cycle {
+		cycle := evening
+		cycle.Weekday, cycle.Monthday, cycle.Interval = weekday, monthday, interval
+		return cycle
+	}
+	tests := []struct {
+		cycle domain.Cycle
+		de    string
+		en    string
+	}{
+		{with(0, 0, 0), "Jeden Tag, 18:00–20:00", "Every day, 18:00–20:00"},
+		{with(0, 0, 2), "Jeden zweiten Tag, 18:00–20:00", "Every other day, 18:00–20:00"},
+		{with(0, 0, 3), "Alle 3 Tage, 18:00–20:00", "Every 3 days, 18:00–20:00"},
+		{with(5, 0, 1), "Jeden Freitag, 18:00–20:00", "Every Friday, 18:00–20:00"},
+		{with(7, 0, 2), "Jeden zweiten Sonntag, 18:00–20:00", "Every other Sunday, 18:00–20:00"},
+		{with(7, 0, 3), "Alle 3 Wochen am Sonntag, 18:00–20:00", "Every 3 weeks on Sunday, 18:00–20:00"},
+		{with(4, 1, 0), "Jeden ersten Donnerstag im Monat, 18:00–20:00", "On the first Thursday of every month, 18:00–20:00"},
+		{with(3, -2, 2), "Alle zwei Monate jeden vorletzten Mittwoch, 18:00–20:00", "On the second-to-last Wednesday of every other month, 18:00–20:00"},
+		{with(0, 3, 0), "Am 3. Tag jedes Monats, 18:00–20:00", "On the 3rd day of every month, 18:00–20:00"},
+		{with(0, -1, 2), "Am letzten Tag jedes zweiten Monats, 18:00–20:00", "On the last day of every other month, 18:00–20:00"},
+		{with(0, -12, 3), "Am 12.-letzten Tag alle 3 Monate, 18:00–20:00", "On the 12th-to-last day of every 3 months, 18:00–20:00"},
+		{with(0, -1, 0), "Am letzten Tag jedes Monats, 18:00–20:00", "On the last day of every month, 18:00–20:00"},
+		{domain.Cycle{Weekdays: []int{4, 1}, Begin: 18 * 3600, Duration: 2 * 3600},
+			"Jeden Montag und Donnerstag, 18:00–20:00", "Every Monday and Thursday, 18:00–20:00"},
+		{domain.Cycle{Weekday: 1, Weekdays: []int{3, 5}, Interval: 2, Begin: 18 * 3600, Duration: 2 * 3600},
+			"Jeden zweiten Montag, Mittwoch und Freitag, 18:00–20:00", "Every other Monday, Wednesday and Friday, 18:00–20:00"},
+		{domain.Cycle{Weekdays: []int{1, 4}, Monthday: 1, Begin: 18 * 3600, Duration: 2 * 3600},
+			"Jeden ersten Montag und Donnerstag im Monat, 18:00–20:00", "On the first Monday and Thursday of every month, 18:00–20:00"},
+		{domain.Cycle{Weekday: 6, Monthday: 1, Month: 6, Begin: 14 * 3600, Duration: 4 * 3600},
+			"Jedes Jahr am ersten Samstag im Juni, 14:00–18:00", "Every year on the first Saturday of June, 14:00–18:00"},
+		{domain.Cycle{Monthday: 24, Month: 12, Interval: 2, Begin: 14 * 3600},
+			"Alle zwei Jahre am 24. Dezember, 14:00", "Every other year on the 24th day of December, 14:00"},
+		{domain.Cycle{Monthday: -1, Month: 2, Interval: 4, Begin: 14 * 3600},
+			"Alle 4 Jahre am letzten Tag im Februar, 14:00", "Every 4 years on the last day of February, 14:00"},
+		{domain.Cycle{Weekday: 6, Begin: 22 * 3600, Duration: 3 * 3600}, "Jeden Samstag, 22:00–01:00", "Every Saturday, 22:00–01:00"},
+		{domain.Cycle{Weekday: 1, Begin: 9*3600 + 30*60}, "Jeden Montag, 09:30", "Every Monday, 09:30"},
+	}
+	for _, tt := range tests {
+		if got := DescribeCycle(tt.cycle, "de"); got != tt.de {
+			t.Errorf("DescribeCycle(%+v, de) = %q, want %q", tt.cycle, got, tt.de)
+		}
+		if got := DescribeCycle(tt.cycle, "en"); got != tt.en {
+			t.Errorf("DescribeCycle(%+v, en) = %q, want %q", tt.cycle, got, tt.en)
+		}
+	}
+}
+
+func TestValidateCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		cycle   domain.Cycle
+		wantErr bool
+	}{
+		{"every day", domain.Cycle{}, false},
+		{"Monday and Thursday", domain.Cycle{Weekdays: []int{1, 4}}, false},
+		{"first Saturday of June", domain.Cycle{Weekday: 6, Monthday: 1, Month: 6}, false},
+		{"29th of February", domain.Cycle{Monthday: 29, Month: 2}, false},
+		{"weekdays out of range", domain.Cycle{Weekdays: []int{1, 0}}, true},
+		{"every 14 days with weekdays", domain.Cycle{Weekdays: []int{1}, Interval: 14}, false},
+		{"thirteenth month", domain.Cycle{Monthday: 1, Month: 13}, true},
+		{"yearly without day", domain.Cycle{Month: 6}, true},
+		{"30th of February", domain.Cycle{Monthday: 30, Month: 2}, true},
+		{"every other Sunday", domain.Cycle{Weekday: 7, Interval: 2}, false},
+		{"last day of the month", domain.Cycle{Monthday: -1}, false},
+		{"weekday out of range", domain.Cycle{Weekday: 8}, true},
+		{"negative interval", domain.Cycle{Interval: -1}, true},
+		{"every 14 days", domain.Cycle{Interval: 14}, true},
+		{"sixth Monday", domain.Cycle{Weekday: 1, Monthday: 6}, true},
+		{"32nd day", domain.Cycle{Monthday: 32}, true},
+		{"beginning after midnight", domain.Cycle{Begin: 24 * 3600}, true},
+		{"negative duration", domain.Cycle{Duration: -60}, true},
+		{"end before start", domain.Cycle{
+			Startdate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			Enddate:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCycle(tt.cycle); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCycle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
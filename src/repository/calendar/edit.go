@@ -0,0 +1,59 @@
+package calendar
+
+import (
+	"pkv/api/src/domain"
+	"time"
+)
+
+// Day returns the occurrences of the cycles and exceptions on the given date, ignoring the time zone
+func Day(cycles []domain.Cycle, exceptions []domain.Exception, date time.Time) []domain.Occurrence {
+	date = civil(date, time.UTC)
+	occurrences := ComputeOccurrences(cycles, exceptions, date, date.AddDate(0, 0, 1), time.UTC)
+	for i := range occurrences {
+		occurrences[i].Start = time.Time{}
+		occurrences[i].End = time.Time{}
+	}
+	return occurrences
+}
+
+// ReplaceDay returns the exceptions with the ones on the given date replaced by exceptions producing exactly the given
+// occurrences. If there are no occurrences left, the day is cancelled for the given reason.
+func ReplaceDay(exceptions []domain.Exception, date time.Time, occurrences []domain.Occurrence, reason string) []domain.Exception {
+	date = civil(date, time.UTC)
+	result := make([]domain.Exception, 0, len(exceptions)+len(occurrences))
+	for _, exception := range exceptions {
+		if !civil(exception.Date, time.UTC).Equal(date) {
+			result = append(result, exception)
+		}
+	}
+	if len(occurrences) == 0 {
+		return append(result, domain.Exception{Date: date, Reason: reason})
+	}
+	for _, occurrence := range occurrences {
+		result = append(result, domain.Exception{
+			Date:       date,
+			Begin:      occurrence.Begin,
+			Duration:   occurrence.Duration,
+			LocationId: occurrence.LocationId,
+			Reason:     occurrence.Reason,
+		})
+	}
+	return result
+}
+
+// Overlap returns the first pair of occurrences on the same day that take place at the same time, if any. Occurrences
+// without a duration overlap with those beginning at the same time.
+func Overlap(occurrences []domain.Occurrence) (domain.Occurrence, domain.Occurrence, bool) {
+	sorted := append([]domain.Occurrence{}, occurrences...)
+	SortOccurrences(sorted)
+	for i := 1; i < len(sorted); i++ {
+		previous, current := sorted[i-1], sorted[i]
+		if !previous.Date.Equal(current.Date) {
+			continue
+		}
+		if current.Begin == previous.Begin || current.Begin < previous.Begin+previous.Duration {
+			return previous, current, true
+		}
+	}
+	return domain.Occurrence{}, domain.Occurrence{}, false
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// secondsPerDay is the number of seconds between two midnights without DST transition
+const secondsPerDay = 24 * 60 * 60
+
+// ValidateCycle enforces the rules documented in domain.Cycle and rejects impossible values
+func ValidateCycle(cycle domain.Cycle) error {
+	if cycle.Weekday < 0 || cycle.Weekday > 7 {
+		return t.Errorf("the weekday needs to be between 1 (Monday) and 7 (Sunday), or 0 for any day")
+	}
+	for _, weekday := range cycle.Weekdays {
+		if weekday < 1 || weekday > 7 {
+			return t.Errorf("the weekdays need to be between 1 (Monday) and 7 (Sunday)")
+		}
+	}
+	if cycle.Month < 0 || cycle.Month > 12 {
+		return t.Errorf("the month needs to be between 1 and 12, or 0 for cycles that do not recur yearly")
+	}
+	if cycle.Interval < 0 {
+		return t.Errorf("the interval cannot be negative")
+	}
+	weekdays := Weekdays(cycle)
+	if len(weekdays) == 0 && cycle.Monthday == 0 && cycle.Interval > 0 && cycle.Interval%7 == 0 {
+		return t.Errorf("an interval of %d days needs to be given as a weekday with an interval of %d weeks", cycle.Interval, cycle.Interval/7)
+	}
+	if len(weekdays) > 0 && (cycle.Monthday < -5 || cycle.Monthday > 5) {
+		return t.Errorf("a weekday can only occur up to five times in a month, %d is not possible", cycle.Monthday)
+	}
+	if len(weekdays) == 0 && (cycle.Monthday < -31 || cycle.Monthday > 31) {
+		return t.Errorf("a month has up to 31 days, %d is not possible", cycle.Monthday)
+	}
+	if cycle.Month != 0 && cycle.Monthday == 0 {
+		return t.Errorf("yearly cycles need a day of the month")
+	}
+	if cycle.Month != 0 && len(weekdays) == 0 {
+		// 2024 is a leap year, so every day that exists in the month is found
+		if _, ok := validDayInMonth(2024, time.Month(cycle.Month), cycle.Monthday, 0); !ok {
+			return t.Errorf("the day %d does not exist in month %d", cycle.Monthday, cycle.Month)
+		}
+	}
+	if cycle.Begin < 0 || cycle.Begin >= secondsPerDay {
+		return t.Errorf("the beginning needs to be within the day")
+	}
+	if cycle.Duration < 0 {
+		return t.Errorf("the duration cannot be negative")
+	}
+	if !cycle.Startdate.IsZero() && !cycle.Enddate.IsZero() && !cycle.Enddate.After(cycle.Startdate) {
+		return t.Errorf("the end date needs to be after the start date")
+	}
+	return nil
+}
+
+// ValidateException rejects exceptions without date or with impossible times
+func ValidateException(exception domain.Exception) error {
+	if exception.Date.IsZero() {
+		return t.Errorf("the date is missing")
+	}
+	if exception.Begin < 0 || exception.Begin >= secondsPerDay {
+		return t.Errorf("the beginning needs to be within the day")
+	}
+	if exception.Duration < 0 {
+		return t.Errorf("the duration cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package calendar
+
+import (
+	"log"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/dpv"
+	"sync"
+	"time"
+	_ "time/tzdata" // time zones must not depend on the system the server runs on
+)
+
+// DefaultTimezone is used if neither the training, its location nor the configuration name a time zone
+const DefaultTimezone = "Europe/Berlin"
+
+var zones sync.Map
+
+// Zone returns the time zone of the first valid IANA name given, falling back to the configured time zone
+func Zone(names ...string) *time.Location {
+	if dpv.ConfigInstance != nil {
+		names = append(names, dpv.ConfigInstance.Settings.Timezone)
+	}
+	names = append(names, DefaultTimezone)
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if zone, ok := zones.Load(name); ok {
+			return zone.(*time.Location)
+		}
+		zone, err := time.LoadLocation(name)
+		if err != nil {
+			log.Printf("ignoring time zone %s: %v", name, err)
+			continue
+		}
+		zones.Store(name, zone)
+		return zone
+	}
+	return time.UTC
+}
+
+// Instant returns the point in time the given number of seconds after midnight of a date, as shown by a wall clock
+// in the zone. Seconds beyond one day roll over into the following days. The wall clock time is kept on days with a
+// DST transition, so a training at 19:00 stays at 19:00. Wall clock times skipped by a transition are moved forward.
+func Instant(date time.Time, seconds int, zone *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, seconds, 0, zone)
+}
+
+// Localise sets Start and End of an occurrence to the instants at which it begins and ends in the zone
+func Localise(occurrence domain.Occurrence, zone *time.Location) domain.Occurrence {
+	occurrence.Start = Instant(occurrence.Date, occurrence.Begin, zone)
+	occurrence.End = occurrence.Start.Add(time.Duration(occurrence.Duration) * time.Second)
+	return occurrence
+}
+
+// civil returns midnight of the date in the given location, regardless of the location the date has been stored in
+func civil(date time.Time, location *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+}
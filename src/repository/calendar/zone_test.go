@@ -0,0 +1,101 @@
+package calendar
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func TestComputeOccurrencesAcrossTransitions(t *testing.T) {
+	berlin := Zone("Europe/Berlin")
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		cycle     domain.Cycle
+		start     time.Time
+		end       time.Time
+		wantStart []time.Time
+		wantEnd   []time.Time
+	}{
+		{"19:00 stays at 19:00 in March",
+			domain.Cycle{Begin: 19 * 3600, Duration: 7200},
+			day(3, 30), day(4, 1),
+			[]time.Time{utc(3, 30, 18, 0), utc(3, 31, 17, 0)},
+			[]time.Time{utc(3, 30, 20, 0), utc(3, 31, 19, 0)},
+		},
+		{"19:00 stays at 19:00 in October",
+			domain.Cycle{Begin: 19 * 3600, Duration: 7200},
+			day(10, 26), day(10, 28),
+			[]time.Time{utc(10, 26, 17, 0), utc(10, 27, 18, 0)},
+			[]time.Time{utc(10, 26, 19, 0), utc(10, 27, 20, 0)},
+		},
+		{"training crossing midnight ends the next day",
+			domain.Cycle{Weekday: 6, Begin: 22 * 3600, Duration: 3 * 3600},
+			day(10, 19), day(10, 27),
+			[]time.Time{utc(10, 19, 20, 0), utc(10, 26, 20, 0)},
+			[]time.Time{utc(10, 19, 23, 0), utc(10, 26, 23, 0)},
+		},
+		{"training crossing midnight and the October transition lasts its duration",
+			domain.Cycle{Weekday: 7, Begin: 3600, Duration: 3 * 3600},
+			day(10, 27), day(10, 28),
+			[]time.Time{utc(10, 26, 23, 0)},
+			[]time.Time{utc(10, 27, 2, 0)},
+		},
+		{"training in the gap of the March transition is moved forward",
+			domain.Cycle{Weekday: 7, Begin: 2*3600 + 1800, Duration: 3600},
+			day(3, 31), day(4, 1),
+			[]time.Time{utc(3, 31, 1, 30)},
+			[]time.Time{utc(3, 31, 2, 30)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeOccurrences([]domain.Cycle{tt.cycle}, nil, tt.start, tt.end, berlin)
+			if len(got) != len(tt.wantStart) {
+				t.Fatalf("ComputeOccurrences() returned %d occurrences, want %d: %v", len(got), len(tt.wantStart), got)
+			}
+			for i, occurrence := range got {
+				if !occurrence.Start.Equal(tt.wantStart[i]) || !occurrence.End.Equal(tt.wantEnd[i]) {
+					t.Errorf("occurrence %d from %v to %v, want from %v to %v", i, occurrence.Start, occurrence.End, tt.wantStart[i], tt.wantEnd[i])
+				}
+				if occurrence.Start.Location() != berlin {
+					t.Errorf("occurrence %d is in %v, want %v", i, occurrence.Start.Location(), berlin)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeDaysInOtherLocations(t *testing.T) {
+	newYork := Zone("America/New_York")
+	// the cycle has been stored in UTC, but days are requested in New York
+	cycle := domain.Cycle{Weekday: 5, Startdate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	got := ComputeDays(cycle, time.Date(2024, 3, 1, 0, 0, 0, 0, newYork), time.Date(2024, 3, 16, 0, 0, 0, 0, newYork))
+	want := []time.Time{
+		time.Date(2024, 3, 1, 0, 0, 0, 0, newYork),
+		time.Date(2024, 3, 8, 0, 0, 0, 0, newYork),
+		time.Date(2024, 3, 15, 0, 0, 0, 0, newYork),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ComputeDays() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("ComputeDays()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestZone(t *testing.T) {
+	if got := Zone("", "Not/A_Zone", "Europe/Lisbon").String(); got != "Europe/Lisbon" {
+		t.Errorf("Zone() = %v, want Europe/Lisbon", got)
+	}
+	if got := Zone().String(); got != DefaultTimezone {
+		t.Errorf("Zone() = %v, want %v", got, DefaultTimezone)
+	}
+}
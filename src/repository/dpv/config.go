@@ -52,11 +52,24 @@ type Config struct {
 		Exiftool string `yaml:"exiftool"`
 		Python   string `yaml:"python"`
 		Account  string `yaml:"account"`
+
+		SchoolHolidays string `yaml:"school_holidays"`
 	}
+	Translation struct {
+		Enabled  bool     `yaml:"enabled"`  // whether descriptions are translated automatically
+		Provider string   `yaml:"provider"` // deepl, libretranslate, gemini or fake
+		Url      string   `yaml:"url"`      // of the LibreTranslate server or the Gemini proxy
+		Key      string   `yaml:"key"`      // of the LibreTranslate server, if it requires one
+		Glossary []string `yaml:"glossary"` // terms that are never translated in addition to the built-in ones
+	} `yaml:"translation"`
 	Settings struct {
 		Version   string
 		Languages []Language `yaml:"languages"`
 		UserTypes []string   `yaml:"user_types"`
+		Timezone  string     `yaml:"timezone"`
+
+		RejectConflicts   bool `yaml:"reject_conflicts"`   // whether writing a training overlapping with another one at the same location fails, unless a request says otherwise
+		TranslationBudget int  `yaml:"translation_budget"` // characters that may be translated automatically per month, no limit if 0
 	} `yaml:"settings"`
 	Path string
 }
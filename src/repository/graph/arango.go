@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
 	"github.com/arangodb/go-driver/v2/connection"
 	"log"
 	"math/rand"
@@ -102,14 +103,23 @@ func GetOrCreateCollection(db arangodb.Database, name string, edges bool) (arang
 
 var fields map[string]arangodb.ArangoSearchElementProperties
 
+// TrigramAnalyzer splits lower-case text into sequences of three characters for fuzzy matching with NGRAM_MATCH, which
+// also finds parts of German compound words
+const TrigramAnalyzer = "trigram"
+
+// FieldsForAllLanguages indexes title and text of the description in every configured language with the analyzer of
+// that language and the TrigramAnalyzer
 func FieldsForAllLanguages(config *dpv.Config) map[string]arangodb.ArangoSearchElementProperties {
 	if fields == nil {
 		fields = make(map[string]arangodb.ArangoSearchElementProperties)
 		for _, language := range config.Settings.Languages {
 			fields[language.Key] = arangodb.ArangoSearchElementProperties{
 				Fields: map[string]arangodb.ArangoSearchElementProperties{
+					"title": {
+						Analyzers: []string{"text_" + language.Key, TrigramAnalyzer},
+					},
 					"text": {
-						Analyzers: []string{"text_" + language.Key},
+						Analyzers: []string{"text_" + language.Key, TrigramAnalyzer},
 					},
 				},
 			}
@@ -118,27 +128,205 @@ func FieldsForAllLanguages(config *dpv.Config) map[string]arangodb.ArangoSearchE
 	return fields
 }
 
-func CreateViewIfNotExists(db arangodb.Database, config *dpv.Config, name string) error {
-	ok, err := db.ViewExists(context.Background(), name+"-descriptions")
-	if err != nil {
-		return t.Errorf("could not check if view for collection %v exists: %w", name, err)
+// EnsureAnalyzers creates the text analyzer of every configured language that is neither built into ArangoDB nor has
+// been created before
+func EnsureAnalyzers(db arangodb.Database, config *dpv.Config) error {
+	for _, language := range config.Settings.Languages {
+		name := "text_" + language.Key
+		if _, err := db.Analyzer(context.Background(), name); err == nil {
+			continue
+		} else if !shared.IsNotFound(err) {
+			return t.Errorf("could not look up analyzer %s: %w", name, err)
+		}
+		accent, stemming := false, true
+		if _, _, err := db.EnsureCreatedAnalyzer(context.Background(), &arangodb.AnalyzerDefinition{
+			Name: name,
+			Type: arangodb.ArangoSearchAnalyzerTypeText,
+			Properties: arangodb.ArangoSearchAnalyzerProperties{
+				Locale:    language.Key,
+				Case:      arangodb.ArangoSearchCaseLower,
+				Accent:    &accent,
+				Stemming:  &stemming,
+				Stopwords: []string{},
+			},
+			Features: []arangodb.ArangoSearchFeature{
+				arangodb.ArangoSearchFeatureFrequency,
+				arangodb.ArangoSearchFeatureNorm,
+				arangodb.ArangoSearchFeaturePosition,
+			},
+		}); err != nil {
+			return t.Errorf("could not create analyzer %s: %w", name, err)
+		}
 	}
-	if !ok {
-		_, err := db.CreateArangoSearchView(context.Background(), name+"-descriptions", &arangodb.ArangoSearchViewProperties{
-			Links: map[string]arangodb.ArangoSearchElementProperties{
-				"users": {
-					Fields: map[string]arangodb.ArangoSearchElementProperties{
-						"descriptions": {
-							Fields: FieldsForAllLanguages(config),
-						},
+	accent, streamType := false, arangodb.ArangoSearchNGramStreamUTF8
+	size, preserveOriginal := int64(3), false
+	if _, _, err := db.EnsureCreatedAnalyzer(context.Background(), &arangodb.AnalyzerDefinition{
+		Name: TrigramAnalyzer,
+		Type: arangodb.ArangoSearchAnalyzerTypePipeline,
+		Properties: arangodb.ArangoSearchAnalyzerProperties{
+			Pipeline: []arangodb.ArangoSearchAnalyzerPipeline{
+				{
+					Type:       arangodb.ArangoSearchAnalyzerTypeNorm,
+					Properties: arangodb.ArangoSearchAnalyzerProperties{Locale: "de", Case: arangodb.ArangoSearchCaseLower, Accent: &accent},
+				},
+				{
+					Type:       arangodb.ArangoSearchAnalyzerTypeNGram,
+					Properties: arangodb.ArangoSearchAnalyzerProperties{Min: &size, Max: &size, PreserveOriginal: &preserveOriginal, StreamType: &streamType},
+				},
+			},
+		},
+		Features: []arangodb.ArangoSearchFeature{
+			arangodb.ArangoSearchFeatureFrequency,
+			arangodb.ArangoSearchFeaturePosition,
+		},
+	}); err != nil {
+		return t.Errorf("could not create analyzer %s: %w", TrigramAnalyzer, err)
+	}
+	stemming := false
+	if _, _, err := db.EnsureCreatedAnalyzer(context.Background(), &arangodb.AnalyzerDefinition{
+		Name: WordsAnalyzer,
+		Type: arangodb.ArangoSearchAnalyzerTypeText,
+		Properties: arangodb.ArangoSearchAnalyzerProperties{
+			Locale:    "de",
+			Case:      arangodb.ArangoSearchCaseLower,
+			Accent:    &accent,
+			Stemming:  &stemming,
+			Stopwords: []string{},
+		},
+		Features: []arangodb.ArangoSearchFeature{
+			arangodb.ArangoSearchFeatureFrequency,
+			arangodb.ArangoSearchFeatureNorm,
+		},
+	}); err != nil {
+		return t.Errorf("could not create analyzer %s: %w", WordsAnalyzer, err)
+	}
+	return nil
+}
+
+// viewProperties links the descriptions of the collection to its view
+func viewProperties(config *dpv.Config, name string) arangodb.ArangoSearchViewProperties {
+	return arangodb.ArangoSearchViewProperties{
+		Links: map[string]arangodb.ArangoSearchElementProperties{
+			name: {
+				Fields: map[string]arangodb.ArangoSearchElementProperties{
+					"descriptions": {
+						Fields: FieldsForAllLanguages(config),
 					},
 				},
 			},
-		})
-		if err != nil {
-			return t.Errorf("could not create view for collection %v: %w", name, err)
+		},
+	}
+}
+
+// WordsAnalyzer splits text into lower-case words without accents for prefix matching with STARTS_WITH
+const WordsAnalyzer = "words"
+
+// autocompleteProperties links the names of users, the cities of locations and the titles of all descriptions to the
+// autocomplete view
+func autocompleteProperties(config *dpv.Config) arangodb.ArangoSearchViewProperties {
+	words := arangodb.ArangoSearchElementProperties{Analyzers: []string{WordsAnalyzer}}
+	titles := make(map[string]arangodb.ArangoSearchElementProperties)
+	for _, language := range config.Settings.Languages {
+		titles[language.Key] = arangodb.ArangoSearchElementProperties{
+			Fields: map[string]arangodb.ArangoSearchElementProperties{"title": words},
 		}
 	}
+	descriptions := arangodb.ArangoSearchElementProperties{Fields: titles}
+	return arangodb.ArangoSearchViewProperties{
+		Links: map[string]arangodb.ArangoSearchElementProperties{
+			"users":     {Fields: map[string]arangodb.ArangoSearchElementProperties{"name": words}},
+			"locations": {Fields: map[string]arangodb.ArangoSearchElementProperties{"city": words, "descriptions": descriptions}},
+			"trainings": {Fields: map[string]arangodb.ArangoSearchElementProperties{"descriptions": descriptions}},
+			"pages":     {Fields: map[string]arangodb.ArangoSearchElementProperties{"descriptions": descriptions}},
+			"events":    {Fields: map[string]arangodb.ArangoSearchElementProperties{"descriptions": descriptions}},
+		},
+	}
+}
+
+// viewOutdated reports whether a view links other collections than wanted, or misses a wanted field or analyzer.
+// Description views created by earlier versions always linked the users collection.
+func viewOutdated(properties arangodb.ArangoSearchViewProperties, want arangodb.ArangoSearchViewProperties) bool {
+	if len(properties.Links) != len(want.Links) {
+		return true
+	}
+	for collection, link := range want.Links {
+		have, ok := properties.Links[collection]
+		if !ok || fieldsOutdated(have, link) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldsOutdated reports whether a linked field misses an analyzer or a nested field
+func fieldsOutdated(have, want arangodb.ArangoSearchElementProperties) bool {
+	for _, analyzer := range want.Analyzers {
+		if !hasAnalyzer(have.Analyzers, analyzer) {
+			return true
+		}
+	}
+	for name, field := range want.Fields {
+		nested, ok := have.Fields[name]
+		if !ok || fieldsOutdated(nested, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnalyzer reports whether the list contains the analyzer, which ArangoDB may prefix with the name of the database
+func hasAnalyzer(analyzers []string, name string) bool {
+	for _, analyzer := range analyzers {
+		if analyzer == name || strings.HasSuffix(analyzer, "::"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateViewIfNotExists creates the view searching the descriptions of the collection, and migrates a view created by
+// an earlier version by replacing its links
+func CreateViewIfNotExists(db arangodb.Database, config *dpv.Config, name string) error {
+	return ensureView(db, name+"-descriptions", viewProperties(config, name))
+}
+
+// CreateAutocompleteView creates the view suggesting names, cities and titles, and migrates it if the configured
+// languages have changed
+func CreateAutocompleteView(db arangodb.Database, config *dpv.Config) error {
+	return ensureView(db, "autocomplete", autocompleteProperties(config))
+}
+
+// ensureView creates an ArangoSearch view, or replaces the links of an existing one that lacks any of the given ones
+func ensureView(db arangodb.Database, name string, want arangodb.ArangoSearchViewProperties) error {
+	ok, err := db.ViewExists(context.Background(), name)
+	if err != nil {
+		return t.Errorf("could not check if view %v exists: %w", name, err)
+	}
+	if !ok {
+		if _, err := db.CreateArangoSearchView(context.Background(), name, &want); err != nil {
+			return t.Errorf("could not create view %v: %w", name, err)
+		}
+		return nil
+	}
+	view, err := db.View(context.Background(), name)
+	if err != nil {
+		return t.Errorf("could not open view %v: %w", name, err)
+	}
+	search, err := view.ArangoSearchView()
+	if err != nil {
+		return t.Errorf("could not open view %v: %w", name, err)
+	}
+	properties, err := search.Properties(context.Background())
+	if err != nil {
+		return t.Errorf("could not read view %v: %w", name, err)
+	}
+	if !viewOutdated(properties, want) {
+		return nil
+	}
+	log.Printf("migrating view %s", name)
+	if err := search.SetProperties(context.Background(), want); err != nil {
+		return t.Errorf("could not migrate view %v: %w", name, err)
+	}
 	return nil
 }
 
@@ -156,8 +344,8 @@ func Init(configPath string, test bool) (*Db, *dpv.Config, error) {
 	if err != nil {
 		return nil, nil, t.Errorf("could not initialise config instance: %w", err)
 	}
-	if err := t.LoadDE(config); err != nil {
-		log.Printf("Could not load strings_de.ini: %v", err)
+	if err := t.Load(config); err != nil {
+		log.Printf("Could not load message catalogues: %v", err)
 	}
 	c, err := Connect(config, true)
 	if err != nil {
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"pkv/api/src/repository/dpv"
+	"testing"
+)
+
+func Test_viewOutdated(t *testing.T) {
+	fields = nil
+	defer func() { fields = nil }()
+	config := &dpv.Config{}
+	config.Settings.Languages = []dpv.Language{{Key: "de"}, {Key: "en"}}
+	if viewOutdated(viewProperties(config, "trainings"), viewProperties(config, "trainings")) {
+		t.Errorf("viewOutdated() = true for a view created by viewProperties")
+	}
+	broken := viewProperties(config, "trainings")
+	broken.Links = arangodb.ArangoSearchLinks{"users": broken.Links["trainings"]}
+	if !viewOutdated(broken, viewProperties(config, "trainings")) {
+		t.Errorf("viewOutdated() = false for a view linking the users")
+	}
+	textOnly := arangodb.ArangoSearchViewProperties{Links: arangodb.ArangoSearchLinks{
+		"trainings": {Fields: arangodb.ArangoSearchFields{"descriptions": {Fields: arangodb.ArangoSearchFields{
+			"de": {Fields: arangodb.ArangoSearchFields{"text": {}}},
+			"en": {Fields: arangodb.ArangoSearchFields{"text": {}}},
+		}}}},
+	}}
+	if !viewOutdated(textOnly, viewProperties(config, "trainings")) {
+		t.Errorf("viewOutdated() = false for a view without titles")
+	}
+	exact := arangodb.ArangoSearchFields{"title": {Analyzers: []string{"text_de"}}, "text": {Analyzers: []string{"text_de"}}}
+	withoutTrigrams := arangodb.ArangoSearchViewProperties{Links: arangodb.ArangoSearchLinks{
+		"trainings": {Fields: arangodb.ArangoSearchFields{"descriptions": {Fields: arangodb.ArangoSearchFields{
+			"de": {Fields: exact},
+			"en": {Fields: exact},
+		}}}},
+	}}
+	if !viewOutdated(withoutTrigrams, viewProperties(config, "trainings")) {
+		t.Errorf("viewOutdated() = false for a view without fuzzy matching")
+	}
+	if !hasAnalyzer([]string{"dpv::trigram"}, TrigramAnalyzer) || hasAnalyzer([]string{"text_de"}, TrigramAnalyzer) {
+		t.Errorf("hasAnalyzer() does not recognise analyzers prefixed with the database")
+	}
+	autocomplete := autocompleteProperties(config)
+	if viewOutdated(autocomplete, autocompleteProperties(config)) {
+		t.Errorf("viewOutdated() = true for the autocomplete view")
+	}
+	delete(autocomplete.Links, "events")
+	if !viewOutdated(autocomplete, autocompleteProperties(config)) {
+		t.Errorf("viewOutdated() = false for an autocomplete view without events")
+	}
+}
@@ -23,6 +23,12 @@ type PhotoEntity interface {
 	SetPhotos(photos []domain.Photo)
 }
 
+type CommentEntity interface {
+	Entity
+	GetComments() []domain.Comment
+	SetComments(comments []domain.Comment)
+}
+
 func (im *EntityManager[T]) Create(item T, ctx context.Context) error {
 	meta, err := im.Collection.CreateDocument(ctx, item)
 	if err != nil {
@@ -58,6 +64,14 @@ func (im *EntityManager[T]) Update(item T, ctx context.Context) error {
 	return nil
 }
 
+func (im *EntityManager[T]) Replace(item T, ctx context.Context) error {
+	_, err := im.Collection.ReplaceDocument(ctx, item.GetKey(), item)
+	if err != nil {
+		return t.Errorf("could not replace item with key %v: %w", item.GetKey(), err)
+	}
+	return nil
+}
+
 func (im *EntityManager[T]) Delete(item T, ctx context.Context) error {
 	_, err := im.Collection.DeleteDocument(ctx, item.GetKey())
 	if err != nil {
@@ -77,6 +91,25 @@ func (db *Db) TrainingHappensAtLocation(training *domain.Training, location *dom
 	return nil
 }
 
+// MoveTrainingToLocation links the training to the location instead of the one it happened at before, in one
+// transaction so that the training never loses its location
+func (db *Db) MoveTrainingToLocation(trainingKey string, locationKey string, ctx context.Context) error {
+	collections := arangodb.TransactionCollections{Write: []string{"edges"}}
+	return db.Database.WithTransaction(ctx, collections, nil, nil, nil, func(ctx context.Context, tx arangodb.Transaction) error {
+		query := "FOR e IN edges\n"
+		query += "  FILTER e._from == @training AND e.label == \"happens_at\"\n"
+		query += "  REMOVE e IN edges"
+		if err := executeIn(ctx, tx, query, map[string]interface{}{"training": "trainings/" + trainingKey}); err != nil {
+			return t.Errorf("could not remove location of training %s: %w", trainingKey, err)
+		}
+		edge := domain.Edge{From: "trainings/" + trainingKey, To: "locations/" + locationKey, Label: "happens_at"}
+		if err := executeIn(ctx, tx, "INSERT @edge INTO edges", map[string]interface{}{"edge": edge}); err != nil {
+			return t.Errorf("could not build 'happens_at' connection from training %s to location %s: %w", trainingKey, locationKey, err)
+		}
+		return nil
+	})
+}
+
 func (db *Db) UserOrganisesTraining(user domain.User, training domain.Training, ctx context.Context) error {
 	if _, err := db.Edges.CreateDocument(ctx, domain.Edge{
 		From:  "users/" + user.Key,
@@ -88,6 +121,28 @@ func (db *Db) UserOrganisesTraining(user domain.User, training domain.Training,
 	return nil
 }
 
+func (db *Db) EventHappensAtLocation(event *domain.Event, location *domain.Location, ctx context.Context) error {
+	if _, err := db.Edges.CreateDocument(ctx, domain.Edge{
+		From:  "events/" + event.Key,
+		To:    "locations/" + location.Key,
+		Label: "happens_at",
+	}); err != nil {
+		return t.Errorf("could not build 'happens_at' connection from event %s to location %s: %w", event.Key, location.Key, err)
+	}
+	return nil
+}
+
+func (db *Db) UserOrganisesEvent(user domain.User, event domain.Event, ctx context.Context) error {
+	if _, err := db.Edges.CreateDocument(ctx, domain.Edge{
+		From:  "users/" + user.Key,
+		To:    "events/" + event.Key,
+		Label: "organises",
+	}); err != nil {
+		return t.Errorf("could not build 'organises' connection from user %s to event %s: %w", user.Key, event.Key, err)
+	}
+	return nil
+}
+
 func (db *Db) UserOwnsPage(user domain.User, page domain.Page, priority int, ctx context.Context) error {
 	if _, err := db.Edges.CreateDocument(ctx, domain.Edge{
 		From:     "users/" + user.Key,
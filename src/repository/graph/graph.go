@@ -25,7 +25,11 @@ type Db struct {
 	Users          EntityManager[*domain.User]
 	Logins         EntityManager[*domain.Login]
 	Pages          EntityManager[*domain.Page]
+	Attendances    EntityManager[*domain.AttendanceList]
+	Events         EntityManager[*domain.Event]
 	Edges          arangodb.Collection
+	Occurrences    arangodb.Collection
+	Translations   arangodb.Collection
 	LocationsIndex arangodb.IndexResponse
 }
 
@@ -50,17 +54,52 @@ func NewDB(database arangodb.Database, config *dpv.Config) (*Db, error) {
 	if err != nil {
 		return nil, err
 	}
+	attendances, err := NewEntityManager[*domain.AttendanceList](database, "attendances", false, func() *domain.AttendanceList { return new(domain.AttendanceList) })
+	if err != nil {
+		return nil, err
+	}
+	events, err := NewEntityManager[*domain.Event](database, "events", false, func() *domain.Event { return new(domain.Event) })
+	if err != nil {
+		return nil, err
+	}
 	edges, err := GetOrCreateCollection(database, "edges", true)
 	if err != nil {
 		return nil, t.Errorf("could not get or create edges collection: %w", err)
 	}
+	occurrences, err := GetOrCreateCollection(database, "occurrences", false)
+	if err != nil {
+		return nil, t.Errorf("could not get or create occurrences collection: %w", err)
+	}
+	for _, fields := range [][]string{{"date"}, {"trainingId", "date"}, {"resolvedLocationId", "date"}} {
+		if _, _, err := occurrences.EnsurePersistentIndex(context.Background(), fields, nil); err != nil {
+			return nil, t.Errorf("could not ensure persistent index for occurrences: %w", err)
+		}
+	}
+	translations, err := GetOrCreateCollection(database, "translations", false)
+	if err != nil {
+		return nil, t.Errorf("could not get or create translations collection: %w", err)
+	}
+	for _, fields := range [][]string{{"done", "due"}, {"collection", "entityId", "target"}} {
+		if _, _, err := translations.EnsurePersistentIndex(context.Background(), fields, nil); err != nil {
+			return nil, t.Errorf("could not ensure persistent index for translations: %w", err)
+		}
+	}
 	locationsIndex, _, err := locations.Collection.EnsureGeoIndex(context.Background(), []string{"lat", "lng"}, nil)
 	if err != nil {
 		return nil, t.Errorf("could not ensure geo index for locations: %w", err)
 	}
+	if _, _, err := attendances.Collection.EnsurePersistentIndex(context.Background(), []string{"trainingId", "date"}, nil); err != nil {
+		return nil, t.Errorf("could not ensure persistent index for attendances: %w", err)
+	}
+	if err := EnsureAnalyzers(database, config); err != nil {
+		return nil, err
+	}
 	if err := CreateViewIfNotExists(database, config, "trainings"); err != nil {
 		return nil, t.Errorf("could not create view: %w", err)
 	}
+	if err := CreateViewIfNotExists(database, config, "events"); err != nil {
+		return nil, t.Errorf("could not create view: %w", err)
+	}
 	if err := CreateViewIfNotExists(database, config, "locations"); err != nil {
 		return nil, t.Errorf("could not create view: %w", err)
 	}
@@ -70,6 +109,9 @@ func NewDB(database arangodb.Database, config *dpv.Config) (*Db, error) {
 	if err := CreateViewIfNotExists(database, config, "pages"); err != nil {
 		return nil, t.Errorf("could not create view: %w", err)
 	}
+	if err := CreateAutocompleteView(database, config); err != nil {
+		return nil, t.Errorf("could not create view: %w", err)
+	}
 	return &Db{
 		database,
 		trainings,
@@ -77,7 +119,11 @@ func NewDB(database arangodb.Database, config *dpv.Config) (*Db, error) {
 		users,
 		logins,
 		pages,
+		attendances,
+		events,
 		edges,
+		occurrences,
+		translations,
 		locationsIndex,
 	}, nil
 }
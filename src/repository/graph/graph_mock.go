@@ -40,6 +40,7 @@ func CreateDescription(language string, text string, translated bool) domain.Des
 			"Das wird euch sicher ganz gut gefallen",
 			"Das wird euch sicher ganz gut gefallen",
 			translated,
+			"",
 		}
 	case "en":
 		return domain.Description{
@@ -47,6 +48,7 @@ func CreateDescription(language string, text string, translated bool) domain.Des
 			"That will be super cool",
 			"That will be super cool",
 			translated,
+			"",
 		}
 	}
 	return domain.Description{}
@@ -252,6 +254,45 @@ func SampleData(db *Db) {
 	if err := db.UserOrganisesTraining(dpv, meeting, nil); err != nil {
 		log.Fatal(err)
 	}
+	textDe = "Zwei Tage Parkour in Berlin mit Workshops für alle Niveaus und einem gemeinsamen Jam am Sonntag."
+	textEn = "Two days of parkour in Berlin with workshops for all levels and a joint jam on Sunday."
+	jamStart := time.Date(time.Now().Year()+1, time.June, 6, 10, 0, 0, 0, time.UTC)
+	jamDeadline := jamStart.AddDate(0, 0, -14)
+	jam := domain.Event{
+		Entity: domain.Entity{
+			Key: "berlin-jam",
+		},
+		Type: "parkour-jam",
+		Descriptions: map[string]domain.Description{
+			"de": {
+				Title:  "Berlin Jam",
+				Text:   textDe,
+				Render: textDe,
+			},
+			"en": {
+				Title:      "Berlin Jam",
+				Text:       textEn,
+				Render:     textEn,
+				Translated: true,
+			},
+		},
+		Start:                jamStart,
+		End:                  jamStart.AddDate(0, 0, 1).Add(8 * time.Hour),
+		Timezone:             "Europe/Berlin",
+		RegistrationDeadline: &jamDeadline,
+		ParticipantLimit:     80,
+		Price:                15,
+		Currency:             "EUR",
+	}
+	if err := db.Events.Create(&jam, nil); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.EventHappensAtLocation(&jam, &berlin, nil); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.UserOrganisesEvent(dpv, jam, nil); err != nil {
+		log.Fatal(err)
+	}
 	textDe = "Die Satzung des Deutschen Parkour Verbandes e.V."
 	textEn = "The articles of association of the German Parkour Association e.V."
 	page := domain.Page{
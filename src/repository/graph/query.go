@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"fmt"
+	"pkv/api/src/repository/dpv"
 	"strings"
 )
 
@@ -30,3 +32,29 @@ func buildUnsetString(sectionStr string, unsetParts []string) string {
 	}
 	return sectionStr
 }
+
+// buildSearch starts a query with a full-text search for all words of @text in the title or the text of the
+// descriptions in the given language, falling back to the first configured language for others. The best matches
+// come first.
+func buildSearch(variable string, collection string, language string) string {
+	lang := searchLanguage(language)
+	analyzer := "text_" + lang
+	query := fmt.Sprintf("FOR %s IN `%s-descriptions`\n", variable, collection)
+	query += fmt.Sprintf("  SEARCH ANALYZER(TOKENS(@text, \"%s\") ALL == %s.descriptions.%s.title OR TOKENS(@text, \"%s\") ALL == %s.descriptions.%s.text, \"%s\")\n", analyzer, variable, lang, analyzer, variable, lang, analyzer)
+	query += fmt.Sprintf("  SORT BM25(%s) DESC\n", variable)
+	return query
+}
+
+// searchLanguage returns the language if it is configured and the first configured language otherwise
+func searchLanguage(language string) string {
+	languages := dpv.ConfigInstance.Settings.Languages
+	for _, configured := range languages {
+		if configured.Key == language {
+			return language
+		}
+	}
+	if len(languages) == 0 {
+		return "en"
+	}
+	return languages[0].Key
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// AttendanceListKey identifies the attendance list of the occurrence of a training on a date
+func AttendanceListKey(trainingKey string, date time.Time) string {
+	return trainingKey + "-" + date.Format("20060102")
+}
+
+// SaveAttendanceList creates the attendance list of an occurrence or replaces the one recorded before
+func (db *Db) SaveAttendanceList(list *domain.AttendanceList, ctx context.Context) error {
+	list.Key = AttendanceListKey(list.TrainingKey, list.Date)
+	exists, err := db.Attendances.Has(list.Key, ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return db.Attendances.Create(list, ctx)
+	}
+	previous, err := db.Attendances.Read(list.Key, ctx)
+	if err != nil {
+		return err
+	}
+	list.Created = previous.Created
+	return db.Attendances.Replace(list, ctx)
+}
+
+// GetAttendanceLists returns the attendance lists of the trainings between from (inclusive) and to (exclusive),
+// sorted by date
+func (db *Db) GetAttendanceLists(trainingKeys []string, from, to time.Time, ctx context.Context) ([]domain.AttendanceList, error) {
+	query := "FOR list IN attendances\n"
+	query += "  FILTER list.trainingId IN @trainings AND list.date >= @from AND list.date < @to\n"
+	query += "  SORT list.date, list.trainingId\n"
+	query += "  RETURN list"
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"trainings": trainingKeys,
+		"from":      from,
+		"to":        to,
+	}})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+
+	var result []domain.AttendanceList
+	for {
+		var doc domain.AttendanceList
+		_, err := cursor.ReadDocument(ctx, &doc)
+		if shared.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, t.Errorf("obtaining documents failed: %w", err)
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
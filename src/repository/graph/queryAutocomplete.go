@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+)
+
+// Suggest returns the names of users, cities of locations and titles in the given language which contain words
+// starting with every word of the text, best matches first. Equal suggestions of the same type, such as the city of
+// several locations, are only returned once.
+func (db *Db) Suggest(text string, language string, limit int, ctx context.Context) ([]domain.Suggestion, error) {
+	query, bindVars := buildSuggestQuery(text, language, limit)
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: bindVars})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+
+	result := []domain.Suggestion{}
+	for {
+		var doc domain.Suggestion
+		_, err := cursor.ReadDocument(ctx, &doc)
+		if shared.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, t.Errorf("obtaining documents failed: %w", err)
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
+
+// buildSuggestQuery matches the words of the text as prefixes of the words of the names, cities and titles in the
+// autocomplete view. A location suggests its title if that matches, and its city otherwise.
+func buildSuggestQuery(text string, language string, limit int) (string, map[string]interface{}) {
+	types := make(map[string]string, len(SearchTypes))
+	for kind, collection := range SearchTypes {
+		types[collection] = kind
+	}
+	title := fmt.Sprintf("doc.descriptions.%s.title", searchLanguage(language))
+	query := fmt.Sprintf("LET tokens = TOKENS(@text, \"%s\")\n", WordsAnalyzer)
+	query += "FOR doc IN autocomplete\n"
+	query += fmt.Sprintf("  SEARCH ANALYZER(STARTS_WITH(doc.name, tokens, LENGTH(tokens)) OR STARTS_WITH(doc.city, tokens, LENGTH(tokens)) OR STARTS_WITH(%s, tokens, LENGTH(tokens)), \"%s\")\n", title, WordsAnalyzer)
+	query += "  LET score = BM25(doc)\n"
+	query += "  LET collection = PARSE_IDENTIFIER(doc).collection\n"
+	query += fmt.Sprintf("  LET words = TOKENS(%s || \"\", \"%s\")\n", title, WordsAnalyzer)
+	query += "  LET titleMatches = LENGTH(FOR token IN tokens FILTER LENGTH(FOR word IN words FILTER STARTS_WITH(word, token) LIMIT 1 RETURN 1) > 0 RETURN 1) == LENGTH(tokens)\n"
+	query += fmt.Sprintf("  LET suggestion = collection == \"users\" ? doc.name : (collection == \"locations\" AND NOT titleMatches ? doc.city : %s)\n", title)
+	query += "  COLLECT type = TRANSLATE(collection, @types), text = suggestion AGGREGATE best = MAX(score) INTO keys = doc._key\n"
+	query += "  SORT best DESC, text\n"
+	query += "  LIMIT @limit\n"
+	query += "  RETURN {type: type, _key: FIRST(keys), text: text}"
+	return query, map[string]interface{}{
+		"text":  text,
+		"types": types,
+		"limit": limit,
+	}
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"context"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// ReplaceCoaches removes the coaches assigned to the occurrence of the training on the given date and builds a
+// 'coaches' connection from each of the given users instead, in one transaction so that a failure keeps the previous
+// coaches
+func (db *Db) ReplaceCoaches(trainingKey string, date time.Time, userKeys []string, ctx context.Context) error {
+	coachings := make([]domain.Coaching, 0, len(userKeys))
+	for _, userKey := range userKeys {
+		coachings = append(coachings, domain.Coaching{
+			From:  "users/" + userKey,
+			To:    "trainings/" + trainingKey,
+			Label: "coaches",
+			Date:  date,
+		})
+	}
+	collections := arangodb.TransactionCollections{Write: []string{"edges"}}
+	return db.Database.WithTransaction(ctx, collections, nil, nil, nil, func(ctx context.Context, tx arangodb.Transaction) error {
+		query := "FOR e IN edges\n"
+		query += "  FILTER e._to == @training AND e.label == \"coaches\" AND e.date == @date\n"
+		query += "  REMOVE e IN edges"
+		if err := executeIn(ctx, tx, query, map[string]interface{}{"training": "trainings/" + trainingKey, "date": date}); err != nil {
+			return t.Errorf("could not remove coaches of training %s: %w", trainingKey, err)
+		}
+		if len(coachings) == 0 {
+			return nil
+		}
+		if err := executeIn(ctx, tx, "FOR c IN @coachings INSERT c INTO edges", map[string]interface{}{"coachings": coachings}); err != nil {
+			return t.Errorf("could not build 'coaches' connections to training %s: %w", trainingKey, err)
+		}
+		return nil
+	})
+}
+
+// GetCoachings returns the coaches assigned to occurrences of the training between from (inclusive) and to (exclusive)
+func (db *Db) GetCoachings(trainingKey string, from, to time.Time, ctx context.Context) ([]domain.CoachingDTO, error) {
+	query := "FOR e IN edges\n"
+	query += "  FILTER e._to == @id AND e.label == \"coaches\" AND e.date >= @from AND e.date < @to\n"
+	query += "  SORT e.date, e._from\n"
+	query += "  RETURN MERGE(e, {user: UNSET(DOCUMENT(e._from), \"photos\", \"comments\")})"
+	return db.readCoachings(query, "trainings/"+trainingKey, from, to, ctx)
+}
+
+// GetCoachingsOfUser returns the occurrences between from (inclusive) and to (exclusive) the user has been assigned to
+// as coach
+func (db *Db) GetCoachingsOfUser(userKey string, from, to time.Time, ctx context.Context) ([]domain.CoachingDTO, error) {
+	query := "FOR e IN edges\n"
+	query += "  FILTER e._from == @id AND e.label == \"coaches\" AND e.date >= @from AND e.date < @to\n"
+	query += "  SORT e.date, e._to\n"
+	query += "  RETURN e"
+	return db.readCoachings(query, "users/"+userKey, from, to, ctx)
+}
+
+func (db *Db) readCoachings(query string, id string, from, to time.Time, ctx context.Context) ([]domain.CoachingDTO, error) {
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"id":   id,
+		"from": from,
+		"to":   to,
+	}})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+
+	var result []domain.CoachingDTO
+	for {
+		var doc domain.CoachingDTO
+		_, err := cursor.ReadDocument(ctx, &doc)
+		if shared.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, t.Errorf("obtaining documents failed: %w", err)
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"context"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
+	"math"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"strings"
+	"time"
+)
+
+// GetFilteredEvents returns the events matching the filters of the trainings which overlap with the time span between
+// from and to, sorted by their start. A zero from or to leaves the time span open on that side.
+func (db *Db) GetFilteredEvents(options domain.TrainingQueryOptions, from, to time.Time, ctx context.Context) ([]domain.EventDTO, error) {
+	query, bindVars := buildEventQuery(options, from, to)
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: bindVars})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+
+	var result []domain.EventDTO
+	for {
+		var doc domain.EventDTO
+		_, err := cursor.ReadDocument(ctx, &doc)
+		if shared.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, t.Errorf("obtaining documents failed: %w", err)
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
+
+// ReplaceEventLocations links the event to the given locations instead of the ones it happened at before
+func (db *Db) ReplaceEventLocations(eventKey string, locationKeys []string, ctx context.Context) error {
+	query := "FOR e IN edges\n"
+	query += "  FILTER e._from == @event AND e.label == \"happens_at\"\n"
+	query += "  REMOVE e IN edges"
+	if err := db.execute(ctx, query, map[string]interface{}{"event": "events/" + eventKey}); err != nil {
+		return t.Errorf("could not remove locations of event %s: %w", eventKey, err)
+	}
+	for _, locationKey := range locationKeys {
+		if err := db.EventHappensAtLocation(&domain.Event{Entity: domain.Key(eventKey)}, &domain.Location{Entity: domain.Key(locationKey)}, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildEventQuery applies the filters of the trainings to events. An event matches a weekday if it takes place on it,
+// and a city, location or distance if any of its locations does.
+func buildEventQuery(options domain.TrainingQueryOptions, from, to time.Time) (string, map[string]interface{}) {
+	includeSet := options.Include
+	var query string
+	bindVars := make(map[string]interface{})
+	if options.Text != "" {
+		query += buildSearch("event", "events", options.Language)
+		bindVars["text"] = options.Text
+	} else {
+		query += "FOR event IN events\n"
+	}
+	if options.Key != "" {
+		query += "  FILTER event._key == @key\n"
+		bindVars["key"] = options.Key
+	}
+	if !from.IsZero() {
+		query += "  FILTER DATE_TIMESTAMP(event.end) > DATE_TIMESTAMP(@from)\n"
+		bindVars["from"] = from
+	}
+	if !to.IsZero() {
+		query += "  FILTER DATE_TIMESTAMP(event.start) < DATE_TIMESTAMP(@to)\n"
+		bindVars["to"] = to
+	}
+	if options.Weekday != 0 {
+		// the local dates are the first ten characters of the start and end, the week starts on Monday (1)
+		query += "  LET days = MIN([DATE_DIFF(LEFT(event.start, 10), LEFT(event.end, 10), \"d\"), 6])\n"
+		query += "  FILTER @weekday IN (FOR d IN 0..days RETURN (DATE_DAYOFWEEK(DATE_ADD(LEFT(event.start, 10), d, \"day\")) + 6) % 7 + 1)\n"
+		bindVars["weekday"] = options.Weekday
+	}
+	unsetLocation := buildUnsetParts(includeSet, "location_")
+	locationStr := buildUnsetString("location", unsetLocation)
+	query += "  LET locations = (FOR location, e IN OUTBOUND event edges FILTER e.label == \"happens_at\" RETURN " + locationStr + ")\n"
+	if options.City != "" {
+		query += "  FILTER @city IN locations[*].city\n"
+		bindVars["city"] = options.City
+	}
+	if options.LocationKey != "" {
+		query += "  FILTER @locationKey IN locations[*]._key\n"
+		bindVars["locationKey"] = options.LocationKey
+	}
+	if options.MaxDistance > 0 {
+		query += "  FILTER locations[? ANY FILTER GEO_DISTANCE([@lng, @lat], [CURRENT.lng, CURRENT.lat]) <= @maxDistance]\n"
+		bindVars["lat"] = options.Lat
+		bindVars["lng"] = options.Lng
+		bindVars["maxDistance"] = options.MaxDistance
+	}
+	unsetOrganiser := buildUnsetParts(includeSet, "organiser_")
+	organiserStr := buildUnsetString("organiser", unsetOrganiser)
+	query += "  LET organisers = (FOR organiser, e IN 1..1 INBOUND event edges FILTER e.label == \"organises\" RETURN " + organiserStr + ")\n"
+	if options.OrganiserKey != "" {
+		query += "  FILTER @organiserKey IN organisers[*]._key\n"
+		bindVars["organiserKey"] = options.OrganiserKey
+	}
+	query += "  SORT DATE_TIMESTAMP(event.start)\n"
+	if options.Skip > 0 || options.Limit > 0 {
+		if options.Limit == 0 {
+			options.Limit = math.MaxInt
+		}
+		query += "  LIMIT @skip, @limit\n"
+		bindVars["skip"] = options.Skip
+		bindVars["limit"] = options.Limit
+	}
+	eventStr := buildUnsetString("event", buildUnsetParts(includeSet, ""))
+	query += "  RETURN MERGE(" + eventStr + ", {"
+	var sections []string
+	if _, ok := includeSet["location"]; ok {
+		sections = append(sections, "locations: locations")
+	}
+	sections = append(sections, "locationIds: locations[*]._key")
+	if _, ok := includeSet["organisers"]; ok {
+		sections = append(sections, "organisers: organisers")
+	}
+	sections = append(sections, "organiserIds: organisers[*]._key")
+	query += "\n    " + strings.Join(sections, ",\n    ") + "\n"
+	query += "  })"
+
+	return query, bindVars
+}
@@ -2,12 +2,10 @@ package graph
 
 import (
 	"context"
-	"fmt"
 	"github.com/arangodb/go-driver/v2/arangodb"
 	"github.com/arangodb/go-driver/v2/arangodb/shared"
 	"math"
 	"pkv/api/src/domain"
-	"pkv/api/src/repository/dpv"
 	"pkv/api/src/repository/t"
 )
 
@@ -60,19 +58,7 @@ func buildLocationQuery(options domain.LocationQueryOptions) (string, map[string
 		"maxDistance": options.MaxDistance,
 	}
 	if options.Text != "" {
-		lang := options.Language
-		valid := false
-		for _, language := range dpv.ConfigInstance.Settings.Languages {
-			if language.Key == lang {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			lang = "en"
-		}
-		query += "FOR location IN `locations-descriptions`\n"
-		query += fmt.Sprintf(`  SEARCH ANALYZER(TOKENS(@text, "text_%s") ALL == location.descriptions.%s.text, "text_%s")`, lang, lang, lang)
+		query += buildSearch("location", "locations", options.Language)
 		bindVars["text"] = options.Text
 	} else {
 		query += "FOR location IN locations\n"
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// ReplaceStoredOccurrences removes the stored occurrences of the training and stores the given ones instead, in one
+// transaction so that readers never see the training without occurrences
+func (db *Db) ReplaceStoredOccurrences(trainingKey string, occurrences []domain.StoredOccurrence, ctx context.Context) error {
+	collections := arangodb.TransactionCollections{Write: []string{"occurrences"}}
+	return db.Database.WithTransaction(ctx, collections, nil, nil, nil, func(ctx context.Context, tx arangodb.Transaction) error {
+		if err := executeIn(ctx, tx, "FOR o IN occurrences FILTER o.trainingId == @training REMOVE o IN occurrences", map[string]interface{}{"training": trainingKey}); err != nil {
+			return t.Errorf("could not remove occurrences of training %s: %w", trainingKey, err)
+		}
+		if len(occurrences) == 0 {
+			return nil
+		}
+		if err := executeIn(ctx, tx, "FOR o IN @occurrences INSERT o INTO occurrences", map[string]interface{}{"occurrences": occurrences}); err != nil {
+			return t.Errorf("could not store occurrences of training %s: %w", trainingKey, err)
+		}
+		return nil
+	})
+}
+
+// occurrenceWindowKey is the key of the document in the occurrences collection that records their window
+const occurrenceWindowKey = "window"
+
+// GetOccurrenceWindow returns the time span the stored occurrences cover, which is empty if they have never been stored
+func (db *Db) GetOccurrenceWindow(ctx context.Context) (domain.OccurrenceWindow, error) {
+	var window domain.OccurrenceWindow
+	if _, err := db.Occurrences.ReadDocument(ctx, occurrenceWindowKey, &window); err != nil && !shared.IsNotFound(err) {
+		return window, t.Errorf("could not read window of stored occurrences: %w", err)
+	}
+	return window, nil
+}
+
+// SetOccurrenceWindow records the time span the stored occurrences cover
+func (db *Db) SetOccurrenceWindow(window domain.OccurrenceWindow, ctx context.Context) error {
+	query := "UPSERT {_key: @key} INSERT MERGE({_key: @key}, @window) UPDATE @window IN occurrences"
+	if err := db.execute(ctx, query, map[string]interface{}{"key": occurrenceWindowKey, "window": window}); err != nil {
+		return t.Errorf("could not store window of stored occurrences: %w", err)
+	}
+	return nil
+}
+
+// RemoveStoredOccurrences removes the stored occurrences before the given date as well as those of trainings that do
+// not exist anymore
+func (db *Db) RemoveStoredOccurrences(before time.Time, ctx context.Context) error {
+	query := "FOR o IN occurrences\n"
+	query += "  FILTER o._key != @window\n"
+	query += "  FILTER o.date < @before OR DOCUMENT(\"trainings\", o.trainingId) == null\n"
+	query += "  REMOVE o IN occurrences"
+	if err := db.execute(ctx, query, map[string]interface{}{"before": before, "window": occurrenceWindowKey}); err != nil {
+		return t.Errorf("could not remove outdated occurrences: %w", err)
+	}
+	return nil
+}
+
+// GetStoredOccurrences returns the stored occurrences of the trainings between from (inclusive) and to (exclusive)
+func (db *Db) GetStoredOccurrences(trainingKeys []string, from, to time.Time, ctx context.Context) ([]domain.StoredOccurrence, error) {
+	query := "FOR o IN occurrences\n"
+	query += "  FILTER o.date >= @from AND o.date < @to AND o.trainingId IN @trainings\n"
+	query += "  SORT o.date, o.begin\n"
+	query += "  RETURN o"
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"trainings": trainingKeys,
+		"from":      from,
+		"to":        to,
+	}})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+
+	var result []domain.StoredOccurrence
+	for {
+		var doc domain.StoredOccurrence
+		_, err := cursor.ReadDocument(ctx, &doc)
+		if shared.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, t.Errorf("obtaining documents failed: %w", err)
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
+
+// execute runs a query that does not return any documents
+func (db *Db) execute(ctx context.Context, query string, bindVars map[string]interface{}) error {
+	return executeIn(ctx, db.Database, query, bindVars)
+}
+
+// executeIn runs a query that does not return any documents in the database or a transaction
+func executeIn(ctx context.Context, q arangodb.DatabaseQuery, query string, bindVars map[string]interface{}) error {
+	cursor, err := q.Query(ctx, query, &arangodb.QueryOptions{BindVars: bindVars})
+	if err != nil {
+		return t.Errorf("query string invalid: %w", err)
+	}
+	return cursor.Close()
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/dpv"
+)
+
+// referenced maps the kinds of entities descriptions can refer to onto their collections
+var referenced = map[string]string{
+	"user":     "users",
+	"location": "locations",
+	"training": "trainings",
+	"event":    "events",
+	"page":     "pages",
+}
+
+// Resolve returns the label of a referenced entity and whether it exists. Users are labelled by their name, all other
+// entities by the title of their description best matching the language.
+func (db *Db) Resolve(kind, key, language string, ctx context.Context) (string, bool) {
+	collection, ok := referenced[kind]
+	if !ok {
+		return "", false
+	}
+	query := "FOR e IN @@collection\n"
+	query += "  FILTER e._key == @key\n"
+	query += "  RETURN {name: e.name, descriptions: e.descriptions}"
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"@collection": collection,
+		"key":         key,
+	}})
+	if err != nil {
+		return "", false
+	}
+	defer cursor.Close()
+	var entity struct {
+		Name         string              `json:"name"`
+		Descriptions domain.Descriptions `json:"descriptions"`
+	}
+	if _, err := cursor.ReadDocument(ctx, &entity); err != nil {
+		return "", false
+	}
+	if kind == "user" && entity.Name != "" {
+		return entity.Name, true
+	}
+	var fallback []string
+	for _, l := range dpv.ConfigInstance.Settings.Languages {
+		fallback = append(fallback, l.Key)
+	}
+	if l, ok := entity.Descriptions.Negotiate([]string{language}, fallback); ok && entity.Descriptions[l].Title != "" {
+		return entity.Descriptions[l].Title, true
+	}
+	return key, true
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// RegistrationKey identifies the registration of a user for the occurrence of a training on a date, so that every user
+// can register only once per occurrence
+func RegistrationKey(userKey string, trainingKey string, date time.Time) string {
+	return userKey + "-" + trainingKey + "-" + date.Format("20060102")
+}
+
+// UserRegistersForTraining builds a 'registers_for' connection from the user to the training on the given date. It
+// returns false if the user has already registered, keeping the existing registration and thus the place on the
+// waitlist.
+func (db *Db) UserRegistersForTraining(userKey string, trainingKey string, date time.Time, ctx context.Context) (bool, error) {
+	if _, err := db.Edges.CreateDocument(ctx, domain.Registration{
+		Key:     RegistrationKey(userKey, trainingKey, date),
+		From:    "users/" + userKey,
+		To:      "trainings/" + trainingKey,
+		Label:   "registers_for",
+		Date:    date,
+		Created: time.Now().UTC(),
+	}); err != nil {
+		if shared.IsConflict(err) {
+			return false, nil
+		}
+		return false, t.Errorf("could not build 'registers_for' connection from user %s to training %s: %w", userKey, trainingKey, err)
+	}
+	return true, nil
+}
+
+// DeleteRegistration removes the registration of the user for the training on the given date. It returns false if
+// there was none.
+func (db *Db) DeleteRegistration(userKey string, trainingKey string, date time.Time, ctx context.Context) (bool, error) {
+	if _, err := db.Edges.DeleteDocument(ctx, RegistrationKey(userKey, trainingKey, date)); err != nil {
+		if shared.IsNotFound(err) {
+			return false, nil
+		}
+		return false, t.Errorf("could not delete registration of user %s for training %s: %w", userKey, trainingKey, err)
+	}
+	return true, nil
+}
+
+// GetRegistrations returns the registrations for the training on the given date in the order they were made
+func (db *Db) GetRegistrations(trainingKey string, date time.Time, ctx context.Context) ([]domain.RegistrationDTO, error) {
+	query := "FOR e IN edges\n"
+	query += "  FILTER e._to == @training AND e.label == \"registers_for\" AND e.date == @date\n"
+	query += "  SORT e.created, e._key\n"
+	query += "  RETURN MERGE(e, {user: KEEP(DOCUMENT(e._from), \"_key\", \"name\", \"type\")})"
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"training": "trainings/" + trainingKey,
+		"date":     date,
+	}})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+
+	var result []domain.RegistrationDTO
+	for {
+		var doc domain.RegistrationDTO
+		_, err := cursor.ReadDocument(ctx, &doc)
+		if shared.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, t.Errorf("obtaining documents failed: %w", err)
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
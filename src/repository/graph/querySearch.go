@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+)
+
+// SearchTypes lists the types of entities the global search finds, mapped to their collections
+var SearchTypes = map[string]string{
+	"user":     "users",
+	"training": "trainings",
+	"location": "locations",
+	"page":     "pages",
+	"event":    "events",
+}
+
+// fuzziness is the share of the trigrams of the text that need to occur in a title or text for a fuzzy match
+const fuzziness = 0.7
+
+// Search finds the entities of the given types whose descriptions contain the text, ranked by BM25. The facets count
+// all results per type, the results only contain the page following the position given by the options.
+func (db *Db) Search(options domain.SearchQueryOptions, ctx context.Context) (domain.SearchResults, error) {
+	query, bindVars := buildGlobalSearchQuery(options)
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: bindVars})
+	if err != nil {
+		return domain.SearchResults{}, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+	var results domain.SearchResults
+	if _, err := cursor.ReadDocument(ctx, &results); err != nil {
+		return domain.SearchResults{}, t.Errorf("obtaining documents failed: %w", err)
+	}
+	return results, nil
+}
+
+// buildGlobalSearchQuery searches the view of every type. All words of the text need to occur in the title or all in
+// the text, which counts twice, or most of its trigrams need to occur in one of them, which tolerates typos.
+func buildGlobalSearchQuery(options domain.SearchQueryOptions) (string, map[string]interface{}) {
+	lang := searchLanguage(options.Language)
+	analyzer := "text_" + lang
+	bindVars := map[string]interface{}{
+		"text":  options.Text,
+		"limit": options.Limit,
+	}
+	query := "LET results = UNION([], []"
+	for _, kind := range options.Types {
+		title := fmt.Sprintf("doc.descriptions.%s.title", lang)
+		text := fmt.Sprintf("doc.descriptions.%s.text", lang)
+		query += fmt.Sprintf(",\n  (FOR doc IN `%s-descriptions`\n", SearchTypes[kind])
+		query += fmt.Sprintf("    SEARCH BOOST(ANALYZER(TOKENS(@text, \"%s\") ALL == %s OR TOKENS(@text, \"%s\") ALL == %s, \"%s\"), 2)\n", analyzer, title, analyzer, text, analyzer)
+		query += fmt.Sprintf("      OR NGRAM_MATCH(%s, @text, %v, \"%s\") OR NGRAM_MATCH(%s, @text, %v, \"%s\")\n", title, fuzziness, TrigramAnalyzer, text, fuzziness, TrigramAnalyzer)
+		if kind == "user" {
+			title = "doc.name"
+		}
+		query += fmt.Sprintf("    RETURN {type: \"%s\", _key: doc._key, title: %s, text: %s, score: BM25(doc)})", kind, title, text)
+	}
+	query += ")\n"
+	query += "LET facets = MERGE(FOR r IN results COLLECT type = r.type WITH COUNT INTO count RETURN {[type]: count})\n"
+	query += "LET page = (FOR r IN results\n"
+	if options.After != "" {
+		query += "  FILTER r.score < @score OR (r.score == @score AND CONCAT(r.type, \"/\", r._key) > @after)\n"
+		bindVars["score"] = options.Score
+		bindVars["after"] = options.After
+	}
+	query += "  SORT r.score DESC, CONCAT(r.type, \"/\", r._key)\n"
+	query += "  LIMIT @limit\n"
+	query += "  RETURN r)\n"
+	query += "RETURN {results: page, facets: facets}"
+	return query, bindVars
+}
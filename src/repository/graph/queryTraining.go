@@ -2,12 +2,10 @@ package graph
 
 import (
 	"context"
-	"fmt"
 	"github.com/arangodb/go-driver/v2/arangodb"
 	"github.com/arangodb/go-driver/v2/arangodb/shared"
 	"math"
 	"pkv/api/src/domain"
-	"pkv/api/src/repository/dpv"
 	"pkv/api/src/repository/t"
 	"strings"
 )
@@ -43,6 +41,35 @@ func (db *Db) GetTrainings(queryBuilder QueryBuilder, ctx context.Context) ([]do
 	return result, nil
 }
 
+// GetImportedTraining returns the training the organiser has imported from the source with the given id, or nil
+func (db *Db) GetImportedTraining(source string, id string, organiserKey string, ctx context.Context) (*domain.Training, error) {
+	query := `
+        FOR training IN trainings
+        FILTER training.information.importedFrom == @importedFrom AND training.information.importedId == @importedId
+        FILTER @organiserKey IN (FOR organiser, e IN 1..1 INBOUND training edges FILTER e.label == "organises" RETURN organiser._key)
+        LIMIT 1
+        RETURN training
+    `
+	bindVars := map[string]interface{}{
+		"importedFrom": source,
+		"importedId":   id,
+		"organiserKey": organiserKey,
+	}
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: bindVars})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+	training := new(domain.Training)
+	_, err = cursor.ReadDocument(ctx, training)
+	if shared.IsNoMoreDocuments(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, t.Errorf("obtaining documents failed: %w", err)
+	}
+	return training, nil
+}
+
 func buildAllTrainingsQuery() (string, map[string]interface{}) {
 	query := "FOR doc IN trainings RETURN doc"
 	var bindVars map[string]interface{}
@@ -54,23 +81,15 @@ func buildTrainingQuery(options domain.TrainingQueryOptions) (string, map[string
 	var query string
 	bindVars := make(map[string]interface{})
 	if options.Text != "" {
-		lang := options.Language
-		valid := false
-		for _, language := range dpv.ConfigInstance.Settings.Languages {
-			if language.Key == lang {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			lang = "en"
-		}
-		query += "FOR training IN `trainings-descriptions`\n"
-		query += fmt.Sprintf(`  SEARCH ANALYZER(TOKENS(@text, "text_%s") ALL == training.descriptions.%s.text, "text_%s")`, lang, lang, lang)
+		query += buildSearch("training", "trainings", options.Language)
 		bindVars["text"] = options.Text
 	} else {
 		query += "FOR training IN trainings\n"
 	}
+	if options.Key != "" {
+		query += "  FILTER training._key == @key\n"
+		bindVars["key"] = options.Key
+	}
 	if options.Weekday != 0 {
 		query += "  FILTER training.cycles[? ANY FILTER CURRENT.weekday == @weekday]\n"
 		bindVars["weekday"] = options.Weekday
@@ -86,6 +105,12 @@ func buildTrainingQuery(options domain.TrainingQueryOptions) (string, map[string
 		query += "  FILTER @locationKey == location._key\n"
 		bindVars["locationKey"] = options.LocationKey
 	}
+	if options.MaxDistance > 0 {
+		query += "  FILTER location != null AND GEO_DISTANCE([@lng, @lat], [location.lng, location.lat]) <= @maxDistance\n"
+		bindVars["lat"] = options.Lat
+		bindVars["lng"] = options.Lng
+		bindVars["maxDistance"] = options.MaxDistance
+	}
 	unsetOrganiser := buildUnsetParts(includeSet, "organiser_")
 	organiserStr := buildUnsetString("organiser", unsetOrganiser)
 	query += "  LET organisers = (FOR organiser, e IN 1..1 INBOUND training edges FILTER e.label == \"organises\" RETURN " + organiserStr + ")\n"
@@ -103,6 +128,7 @@ func buildTrainingQuery(options domain.TrainingQueryOptions) (string, map[string
 	}
 	unsetTraining := buildUnsetParts(includeSet, "")
 	unsetTraining = appendUnsetPart(unsetTraining, includeSet, "cycles", "cycles")
+	unsetTraining = appendUnsetPart(unsetTraining, includeSet, "exceptions", "exceptions")
 	trainingStr := buildUnsetString("training", unsetTraining)
 	query += "  RETURN MERGE(" + trainingStr + ", {"
 	var sections []string
@@ -121,3 +147,14 @@ func buildTrainingQuery(options domain.TrainingQueryOptions) (string, map[string
 
 	return query, bindVars
 }
+
+// UpdateTrainingExceptions replaces the exceptions of a training, leaving the rest of the document untouched
+func (db *Db) UpdateTrainingExceptions(key string, exceptions []domain.Exception, ctx context.Context) error {
+	if exceptions == nil {
+		exceptions = []domain.Exception{}
+	}
+	if _, err := db.Trainings.Collection.UpdateDocument(ctx, key, map[string]interface{}{"exceptions": exceptions}); err != nil {
+		return t.Errorf("could not update item with key %v: %w", key, err)
+	}
+	return nil
+}
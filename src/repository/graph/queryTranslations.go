@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"context"
+	"github.com/arangodb/go-driver/v2/arangodb"
+	"github.com/arangodb/go-driver/v2/arangodb/shared"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// EnqueueTranslation queues the job, or reschedules the pending job translating the same entity into the same language
+func (db *Db) EnqueueTranslation(job domain.TranslationJob, ctx context.Context) error {
+	query := "UPSERT {collection: @job.collection, entityId: @job.entityId, target: @job.target, done: null}\n"
+	query += "  INSERT @job\n"
+	query += "  UPDATE {source: @job.source, due: @job.due, attempts: null, error: null}\n"
+	query += "  IN translations OPTIONS {keepNull: false}"
+	if err := db.execute(ctx, query, map[string]interface{}{"job": job}); err != nil {
+		return t.Errorf("could not queue translation of %s/%s: %w", job.Collection, job.EntityKey, err)
+	}
+	return nil
+}
+
+// GetDueTranslations returns the pending jobs due at the given time which have failed less than maxAttempts times,
+// the longest waiting first
+func (db *Db) GetDueTranslations(now time.Time, maxAttempts int, limit int, ctx context.Context) ([]domain.TranslationJob, error) {
+	query := "FOR j IN translations\n"
+	query += "  FILTER j.done == null AND j.due <= @now AND (j.attempts == null OR j.attempts < @attempts)\n"
+	query += "  SORT j.due\n"
+	query += "  LIMIT @limit\n"
+	query += "  RETURN j"
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"now":      now,
+		"attempts": maxAttempts,
+		"limit":    limit,
+	}})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+
+	var result []domain.TranslationJob
+	for {
+		var doc domain.TranslationJob
+		_, err := cursor.ReadDocument(ctx, &doc)
+		if shared.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, t.Errorf("obtaining documents failed: %w", err)
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
+
+// UpdateTranslationJob stores the outcome of an attempt to translate
+func (db *Db) UpdateTranslationJob(job domain.TranslationJob, ctx context.Context) error {
+	if _, err := db.Translations.ReplaceDocument(ctx, job.Key, job); err != nil {
+		return t.Errorf("could not update translation %s: %w", job.Key, err)
+	}
+	return nil
+}
+
+// GetDescriptions returns the descriptions of any entity, or nil if it does not exist
+func (db *Db) GetDescriptions(collection string, key string, ctx context.Context) (domain.Descriptions, error) {
+	cursor, err := db.Database.Query(ctx, "RETURN DOCUMENT(@collection, @key).descriptions", &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"collection": collection,
+		"key":        key,
+	}})
+	if err != nil {
+		return nil, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+	var descriptions domain.Descriptions
+	if _, err := cursor.ReadDocument(ctx, &descriptions); err != nil {
+		return nil, t.Errorf("obtaining documents failed: %w", err)
+	}
+	return descriptions, nil
+}
+
+// StoreTranslation stores the translated description of the job, unless the entity has been deleted, its source
+// description has changed since the translation was started, or someone has written the target description meanwhile.
+// It returns whether the translation has been stored.
+func (db *Db) StoreTranslation(job domain.TranslationJob, translation domain.Description, ctx context.Context) (bool, error) {
+	query := "LET doc = DOCUMENT(@collection, @key)\n"
+	query += "FILTER doc != null\n"
+	query += "LET source = doc.descriptions[@source]\n"
+	query += "FILTER CONCAT(@source, \":\", MD5(CONCAT(source.title, \"\\n\", source.text))) == @origin\n"
+	query += "LET target = doc.descriptions[@target]\n"
+	query += "FILTER target == null OR target.translated == true OR (target.title IN [null, \"\"] AND target.text IN [null, \"\"])\n"
+	query += "UPDATE doc WITH {descriptions: {[@target]: @translation}} IN @@collection\n"
+	query += "RETURN true"
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{
+		"collection":  job.Collection,
+		"@collection": job.Collection,
+		"key":         job.EntityKey,
+		"source":      job.Source,
+		"target":      job.Target,
+		"origin":      translation.Source,
+		"translation": translation,
+	}})
+	if err != nil {
+		return false, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+	var stored bool
+	if _, err := cursor.ReadDocument(ctx, &stored); shared.IsNoMoreDocuments(err) {
+		return false, nil
+	} else if err != nil {
+		return false, t.Errorf("obtaining documents failed: %w", err)
+	}
+	return stored, nil
+}
+
+// GetTranslatedCharacters returns the number of characters sent to the translation service by the jobs done since the
+// given time
+func (db *Db) GetTranslatedCharacters(since time.Time, ctx context.Context) (int, error) {
+	query := "RETURN SUM(FOR j IN translations FILTER j.done != null AND j.done >= @since RETURN j.characters)"
+	cursor, err := db.Database.Query(ctx, query, &arangodb.QueryOptions{BindVars: map[string]interface{}{"since": since}})
+	if err != nil {
+		return 0, t.Errorf("query string invalid: %w", err)
+	}
+	defer cursor.Close()
+	var characters int
+	if _, err := cursor.ReadDocument(ctx, &characters); err != nil {
+		return 0, t.Errorf("obtaining documents failed: %w", err)
+	}
+	return characters, nil
+}
+
+// RemoveTranslationJobs removes the jobs done before the given time
+func (db *Db) RemoveTranslationJobs(before time.Time, ctx context.Context) error {
+	query := "FOR j IN translations FILTER j.done != null AND j.done < @before REMOVE j IN translations"
+	if err := db.execute(ctx, query, map[string]interface{}{"before": before}); err != nil {
+		return t.Errorf("could not remove finished translations: %w", err)
+	}
+	return nil
+}
@@ -2,11 +2,9 @@ package graph
 
 import (
 	"context"
-	"fmt"
 	"github.com/arangodb/go-driver/v2/arangodb"
 	"github.com/arangodb/go-driver/v2/arangodb/shared"
 	"pkv/api/src/domain"
-	"pkv/api/src/repository/dpv"
 	"pkv/api/src/repository/t"
 )
 
@@ -61,19 +59,7 @@ func buildUserQuery(options domain.UserQueryOptions) (string, map[string]interfa
 	var query string
 	bindVars := make(map[string]interface{})
 	if options.Text != "" {
-		lang := options.Language
-		valid := false
-		for _, language := range dpv.ConfigInstance.Settings.Languages {
-			if language.Key == lang {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			lang = "en"
-		}
-		query += "FOR user IN `users-descriptions`\n"
-		query += fmt.Sprintf(`  SEARCH ANALYZER(TOKENS(@text, "text_%s") ALL == user.descriptions.%s.text, "text_%s")`, lang, lang, lang)
+		query += buildSearch("user", "users", options.Language)
 		bindVars["text"] = options.Text
 	} else {
 		query += "FOR user IN users\n"
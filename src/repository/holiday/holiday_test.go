@@ -0,0 +1,89 @@
+package holiday
+
+import (
+	"pkv/api/src/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEaster(t *testing.T) {
+	tests := []struct {
+		year int
+		want time.Time
+	}{
+		{2019, date(2019, 4, 21)},
+		{2024, date(2024, 3, 31)},
+		{2025, date(2025, 4, 20)},
+		{2038, date(2038, 4, 25)},
+	}
+	for _, tt := range tests {
+		if got := Easter(tt.year); !got.Equal(tt.want) {
+			t.Errorf("Easter(%d) = %v, want %v", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestPublic(t *testing.T) {
+	var got []string
+	for _, holiday := range Public("NW", 2024) {
+		got = append(got, holiday.Date.Format("01-02")+" "+holiday.Name)
+	}
+	want := []string{
+		"01-01 Neujahr", "03-29 Karfreitag", "04-01 Ostermontag", "05-01 Tag der Arbeit",
+		"05-09 Christi Himmelfahrt", "05-20 Pfingstmontag", "05-30 Fronleichnam", "10-03 Tag der Deutschen Einheit",
+		"11-01 Allerheiligen", "12-25 1. Weihnachtstag", "12-26 2. Weihnachtstag",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Public(NW, 2024) = %v, want %v", got, want)
+	}
+	saxony := Public("SN", 2024)
+	if repentance := saxony[len(saxony)-3]; !repentance.Date.Equal(date(2024, 11, 20)) {
+		t.Errorf("Public(SN, 2024) has %v on %v, want Buß- und Bettag on 2024-11-20", repentance.Name, repentance.Date)
+	}
+	if len(Public("HH", 2017)) != len(Public("HH", 2018))-1 {
+		t.Errorf("Reformationstag is a holiday in Hamburg since 2018")
+	}
+}
+
+func TestState(t *testing.T) {
+	for _, code := range []string{"NW", "nrw", "DE-NW", " NRW "} {
+		if state, ok := State(code); !ok || state != "NW" {
+			t.Errorf("State(%q) = %q, %v, want NW", code, state, ok)
+		}
+	}
+	if _, ok := State("XX"); ok {
+		t.Errorf("State(XX) is known")
+	}
+}
+
+func TestBetween(t *testing.T) {
+	ranges := Ranges{"NW": {{Name: "Osterferien", Start: date(2024, 3, 25), End: date(2024, 3, 29)}}}
+	got := Between(domain.HolidayPause{State: "NRW", Public: true, School: true}, ranges, date(2024, 3, 27), date(2024, 4, 2))
+	want := []domain.Holiday{
+		{Date: date(2024, 3, 27), Name: "Osterferien", School: true},
+		{Date: date(2024, 3, 28), Name: "Osterferien", School: true},
+		{Date: date(2024, 3, 29), Name: "Karfreitag"},
+		{Date: date(2024, 4, 1), Name: "Ostermontag"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Between() = %+v, want %+v", got, want)
+	}
+	if got := Between(domain.HolidayPause{State: "NW", School: true}, ranges, date(2024, 3, 30), date(2024, 4, 2)); len(got) != 0 {
+		t.Errorf("Between() = %+v, want no school holidays", got)
+	}
+}
+
+func TestLoadSchool(t *testing.T) {
+	ranges, err := LoadSchool("../../../school_holidays.example.yml")
+	if err != nil {
+		t.Fatalf("LoadSchool() error = %v", err)
+	}
+	if len(ranges["NW"]) == 0 || ranges["NW"][0].Name != "Osterferien" || !ranges["NW"][0].Start.Equal(date(2024, 3, 25)) {
+		t.Errorf("LoadSchool() = %+v", ranges)
+	}
+}
@@ -0,0 +1,131 @@
+package holiday
+
+import (
+	"pkv/api/src/domain"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// States maps the ISO 3166-2 codes of the German states to their names
+var States = map[string]string{
+	"BB": "Brandenburg",
+	"BE": "Berlin",
+	"BW": "Baden-Württemberg",
+	"BY": "Bayern",
+	"HB": "Bremen",
+	"HE": "Hessen",
+	"HH": "Hamburg",
+	"MV": "Mecklenburg-Vorpommern",
+	"NI": "Niedersachsen",
+	"NW": "Nordrhein-Westfalen",
+	"RP": "Rheinland-Pfalz",
+	"SH": "Schleswig-Holstein",
+	"SL": "Saarland",
+	"SN": "Sachsen",
+	"ST": "Sachsen-Anhalt",
+	"TH": "Thüringen",
+}
+
+// aliases are common abbreviations of states that differ from their ISO 3166-2 codes
+var aliases = map[string]string{
+	"NRW": "NW",
+}
+
+// State normalises a state code such as "nrw" or "DE-NW" to "NW", and returns false for unknown states
+func State(code string) (string, bool) {
+	code = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(code)), "DE-")
+	if alias, ok := aliases[code]; ok {
+		code = alias
+	}
+	_, ok := States[code]
+	return code, ok
+}
+
+// rule is a public holiday observed in some states, or in all states if none are given
+type rule struct {
+	name   string
+	date   func(year int) time.Time
+	states []string
+	since  int
+}
+
+func fixed(month time.Month, day int) func(int) time.Time {
+	return func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func easter(days int) func(int) time.Time {
+	return func(year int) time.Time {
+		return Easter(year).AddDate(0, 0, days)
+	}
+}
+
+// repentance returns Buß- und Bettag, the Wednesday before the 23rd of November
+func repentance(year int) time.Time {
+	date := time.Date(year, time.November, 22, 0, 0, 0, 0, time.UTC)
+	return date.AddDate(0, 0, -(int(date.Weekday())+4)%7)
+}
+
+// rules lists the statutory holidays observed in the whole state. Holidays of single municipalities, such as
+// Fronleichnam in parts of Sachsen and Thüringen or Mariä Himmelfahrt in parts of Bayern, are not included.
+var rules = []rule{
+	{"Neujahr", fixed(time.January, 1), nil, 0},
+	{"Heilige Drei Könige", fixed(time.January, 6), []string{"BW", "BY", "ST"}, 0},
+	{"Internationaler Frauentag", fixed(time.March, 8), []string{"BE"}, 2019},
+	{"Internationaler Frauentag", fixed(time.March, 8), []string{"MV"}, 2023},
+	{"Karfreitag", easter(-2), nil, 0},
+	{"Ostersonntag", easter(0), []string{"BB"}, 0},
+	{"Ostermontag", easter(1), nil, 0},
+	{"Tag der Arbeit", fixed(time.May, 1), nil, 0},
+	{"Christi Himmelfahrt", easter(39), nil, 0},
+	{"Pfingstsonntag", easter(49), []string{"BB"}, 0},
+	{"Pfingstmontag", easter(50), nil, 0},
+	{"Fronleichnam", easter(60), []string{"BW", "BY", "HE", "NW", "RP", "SL"}, 0},
+	{"Mariä Himmelfahrt", fixed(time.August, 15), []string{"SL"}, 0},
+	{"Weltkindertag", fixed(time.September, 20), []string{"TH"}, 2019},
+	{"Tag der Deutschen Einheit", fixed(time.October, 3), nil, 1990},
+	{"Reformationstag", fixed(time.October, 31), []string{"BB", "MV", "SN", "ST", "TH"}, 0},
+	{"Reformationstag", fixed(time.October, 31), []string{"HB", "HH", "NI", "SH"}, 2018},
+	{"Allerheiligen", fixed(time.November, 1), []string{"BW", "BY", "NW", "RP", "SL"}, 0},
+	{"Buß- und Bettag", repentance, []string{"SN"}, 0},
+	{"1. Weihnachtstag", fixed(time.December, 25), nil, 0},
+	{"2. Weihnachtstag", fixed(time.December, 26), nil, 0},
+}
+
+// Public returns the public holidays of a state in the given year, sorted by date
+func Public(state string, year int) []domain.Holiday {
+	var holidays []domain.Holiday
+	for _, rule := range rules {
+		if year < rule.since || (rule.states != nil && !slices.Contains(rule.states, state)) {
+			continue
+		}
+		holidays = append(holidays, domain.Holiday{Date: rule.date(year), Name: rule.name})
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+// Easter returns Easter Sunday of the given year in the Gregorian calendar as UTC midnight, using the anonymous
+// Gregorian algorithm
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,116 @@
+package holiday
+
+import (
+	"gopkg.in/yaml.v3"
+	"os"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/dpv"
+	"pkv/api/src/repository/t"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Range is a period of school holidays, both dates being inclusive
+type Range struct {
+	Name  string    `yaml:"name"`
+	Start time.Time `yaml:"start"`
+	End   time.Time `yaml:"end"`
+}
+
+// Ranges lists the school holidays per state code
+type Ranges map[string][]Range
+
+// LoadSchool reads the school holidays from a YAML file mapping state codes to lists of ranges
+func LoadSchool(filename string) (Ranges, error) {
+	bytes, err := os.ReadFile(filename)
+	if err != nil {
+		wd, _ := os.Getwd()
+		return nil, t.Errorf("could not load school holidays, looking for %v in %v: %w", filename, wd, err)
+	}
+	var ranges Ranges
+	if err := yaml.Unmarshal(bytes, &ranges); err != nil {
+		return nil, t.Errorf("could not decode school holidays: %w", err)
+	}
+	normalised := make(Ranges, len(ranges))
+	for code, list := range ranges {
+		state, ok := State(code)
+		if !ok {
+			return nil, t.Errorf("unknown state %s", code)
+		}
+		for _, r := range list {
+			if r.End.Before(r.Start) {
+				return nil, t.Errorf("the holidays %s in %s end before they start", r.Name, code)
+			}
+		}
+		normalised[state] = append(normalised[state], list...)
+	}
+	return normalised, nil
+}
+
+var school Ranges
+var schoolMutex sync.Mutex
+
+// School returns the school holidays of the file configured as school_holidays, which is loaded on first use.
+// Without such a file, there are no school holidays.
+func School() (Ranges, error) {
+	schoolMutex.Lock()
+	defer schoolMutex.Unlock()
+	if school != nil {
+		return school, nil
+	}
+	if dpv.ConfigInstance == nil || dpv.ConfigInstance.Server.SchoolHolidays == "" {
+		return Ranges{}, nil
+	}
+	ranges, err := LoadSchool(dpv.ConfigInstance.Path + dpv.ConfigInstance.Server.SchoolHolidays)
+	if err != nil {
+		return nil, err
+	}
+	school = ranges
+	return school, nil
+}
+
+// Between returns the holidays a training pauses on between from (inclusive) and to (exclusive), sorted by date. A
+// day that is both a public holiday and part of the school holidays is listed once as public holiday.
+func Between(pause domain.HolidayPause, ranges Ranges, from, to time.Time) []domain.Holiday {
+	state, _ := State(pause.State)
+	from, to = civil(from), civil(to)
+	seen := make(map[time.Time]bool)
+	var holidays []domain.Holiday
+	if pause.Public {
+		for year := from.Year(); year <= to.Year(); year++ {
+			for _, holiday := range Public(state, year) {
+				if !holiday.Date.Before(from) && holiday.Date.Before(to) && !seen[holiday.Date] {
+					seen[holiday.Date] = true
+					holidays = append(holidays, holiday)
+				}
+			}
+		}
+	}
+	if pause.School {
+		for _, r := range ranges[state] {
+			for day := maxDate(civil(r.Start), from); !day.After(civil(r.End)) && day.Before(to); day = day.AddDate(0, 0, 1) {
+				if !seen[day] {
+					seen[day] = true
+					holidays = append(holidays, domain.Holiday{Date: day, Name: r.Name, School: true})
+				}
+			}
+		}
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+// civil returns the date as UTC midnight
+func civil(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func maxDate(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,154 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateTimeFormat is the layout of a floating DATE-TIME value as per RFC 5545 section 3.3.5
+const DateTimeFormat = "20060102T150405"
+
+// DateFormat is the layout of a DATE value as per RFC 5545 section 3.3.4
+const DateFormat = "20060102"
+
+// ContentType is the media type of iCalendar objects as per RFC 5545 section 8.1
+const ContentType = "text/calendar; charset=utf-8"
+
+// Calendar is an iCalendar object containing a list of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT component. Times are written along with the TZID of their location, unless they are in UTC.
+// An event with a RecurrenceId overrides a single instance of the recurring event with the same UID.
+type Event struct {
+	UID          string
+	RecurrenceId time.Time
+	Stamp        time.Time
+	Summary      string
+	Description  string
+	Location     string
+	Lat          float64
+	Lng          float64
+	Start        time.Time
+	End          time.Time
+	RRule        string
+	ExDates      []time.Time
+}
+
+// Bytes serialises the calendar using CRLF line endings and folded content lines. Every time zone used by the events
+// is described by a VTIMEZONE component.
+func (c Calendar) Bytes() []byte {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//Deutscher Parkour Verband//DPV API//DE")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+Escape(c.Name))
+	}
+	for _, zone := range c.zones() {
+		writeTimezone(&b, zone.location, zone.fromYear-1, zone.toYear)
+	}
+	for _, event := range c.Events {
+		event.write(&b)
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func (e Event) write(b *strings.Builder) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+Escape(e.UID))
+	writeLine(b, "DTSTAMP:"+e.Stamp.UTC().Format(DateTimeFormat)+"Z")
+	if !e.RecurrenceId.IsZero() {
+		writeLine(b, formatTime("RECURRENCE-ID", e.RecurrenceId))
+	}
+	writeLine(b, formatTime("DTSTART", e.Start))
+	writeLine(b, formatTime("DTEND", e.End))
+	if e.RRule != "" {
+		writeLine(b, "RRULE:"+e.RRule)
+	}
+	for _, exDate := range e.ExDates {
+		writeLine(b, formatTime("EXDATE", exDate))
+	}
+	if e.Summary != "" {
+		writeLine(b, "SUMMARY:"+Escape(e.Summary))
+	}
+	if e.Description != "" {
+		writeLine(b, "DESCRIPTION:"+Escape(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(b, "LOCATION:"+Escape(e.Location))
+	}
+	if e.Lat != 0 || e.Lng != 0 {
+		writeLine(b, fmt.Sprintf("GEO:%f;%f", e.Lat, e.Lng))
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+// formatTime writes a DATE-TIME property in UTC or with the TZID of the location of the time
+func formatTime(name string, instant time.Time) string {
+	if instant.Location() == time.UTC {
+		return name + ":" + instant.Format(DateTimeFormat) + "Z"
+	}
+	return name + ";TZID=" + instant.Location().String() + ":" + instant.Format(DateTimeFormat)
+}
+
+// usedZone is a time zone used by events between two years
+type usedZone struct {
+	location *time.Location
+	fromYear int
+	toYear   int
+}
+
+func (c Calendar) zones() []usedZone {
+	var zones []usedZone
+	index := make(map[string]int)
+	for _, event := range c.Events {
+		location := event.Start.Location()
+		if location == time.UTC {
+			continue
+		}
+		year := event.Start.Year()
+		i, ok := index[location.String()]
+		if !ok {
+			index[location.String()] = len(zones)
+			zones = append(zones, usedZone{location, year, year})
+			continue
+		}
+		zones[i].fromYear = min(zones[i].fromYear, year)
+		zones[i].toYear = max(zones[i].toYear, year)
+	}
+	return zones
+}
+
+// Escape escapes a TEXT value as per RFC 5545 section 3.3.11
+func Escape(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// writeLine folds lines longer than 75 octets without splitting UTF-8 sequences, see RFC 5545 section 3.1
+func writeLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space counts towards the limit
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
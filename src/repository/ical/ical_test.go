@@ -0,0 +1,190 @@
+package ical
+
+import (
+	"pkv/api/src/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRRule(t *testing.T) {
+	zone, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("time zone missing: %v", err)
+	}
+	tests := []struct {
+		name  string
+		cycle domain.Cycle
+		want  string
+	}{
+		{"every day", domain.Cycle{}, "FREQ=DAILY"},
+		{"every third day", domain.Cycle{Interval: 3}, "FREQ=DAILY;INTERVAL=3"},
+		{"every Friday", domain.Cycle{Weekday: 5}, "FREQ=WEEKLY;BYDAY=FR"},
+		{"every second Sunday", domain.Cycle{Weekday: 7, Interval: 2}, "FREQ=WEEKLY;BYDAY=SU;INTERVAL=2"},
+		{"first Thursday of the month", domain.Cycle{Weekday: 4, Monthday: 1}, "FREQ=MONTHLY;BYDAY=TH;BYSETPOS=1"},
+		{"last Wednesday every two months", domain.Cycle{Weekday: 3, Monthday: -1, Interval: 2}, "FREQ=MONTHLY;BYDAY=WE;BYSETPOS=-1;INTERVAL=2"},
+		{"third day of the month", domain.Cycle{Monthday: 3}, "FREQ=MONTHLY;BYMONTHDAY=3"},
+		{"Monday and Thursday", domain.Cycle{Weekdays: []int{1, 4}}, "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"first Monday and Thursday of the month", domain.Cycle{Weekdays: []int{1, 4}, Monthday: 1}, "FREQ=MONTHLY;BYDAY=1MO,1TH"},
+		{"last day of the month", domain.Cycle{Monthday: -1}, "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{"first Saturday of June", domain.Cycle{Weekday: 6, Monthday: 1, Month: 6}, "FREQ=YEARLY;BYMONTH=6;BYDAY=1SA"},
+		{"Christmas Eve every other year", domain.Cycle{Monthday: 24, Month: 12, Interval: 2}, "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=24;INTERVAL=2"},
+		{"until end date", domain.Cycle{Weekday: 1, Enddate: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}, "FREQ=WEEKLY;BYDAY=MO;UNTIL=20240630T215959Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RRule(tt.cycle, zone)
+			if err != nil {
+				t.Fatalf("RRule() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := RRule(domain.Cycle{Weekday: 8}, zone); err == nil {
+		t.Errorf("RRule() accepted weekday 8")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	got := Escape("Halle 1, Eingang B; bitte\r\nSchuhe mitbringen \\o/")
+	want := `Halle 1\, Eingang B\; bitte\nSchuhe mitbringen \\o/`
+	if got != want {
+		t.Errorf("Escape() = %v, want %v", got, want)
+	}
+}
+
+func Test_writeLine(t *testing.T) {
+	var b strings.Builder
+	line := "DESCRIPTION:" + strings.Repeat("ä", 100)
+	writeLine(&b, line)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected line to be folded, got %d lines", len(lines))
+	}
+	var unfolded string
+	for i, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("line %d has %d octets", i, len(l))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(l, " ") {
+				t.Errorf("line %d does not start with a space", i)
+			}
+			l = l[1:]
+		}
+		unfolded += l
+	}
+	if unfolded != line {
+		t.Errorf("unfolded line = %v, want %v", unfolded, line)
+	}
+}
+
+func TestCalendar_Bytes(t *testing.T) {
+	calendar := Calendar{Name: "Parkour", Events: []Event{{
+		UID:     "training-1-cycle-0@example.org",
+		Stamp:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Summary: "Training",
+		Start:   time.Date(2024, 1, 5, 18, 0, 0, 0, time.UTC),
+		End:     time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC),
+		RRule:   "FREQ=WEEKLY;BYDAY=FR",
+		ExDates: []time.Time{time.Date(2024, 1, 12, 18, 0, 0, 0, time.UTC)},
+		Lat:     53.5,
+		Lng:     10,
+	}, {
+		UID:          "training-1-cycle-0@example.org",
+		RecurrenceId: time.Date(2024, 1, 19, 18, 0, 0, 0, time.UTC),
+		Stamp:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Start:        time.Date(2024, 1, 19, 19, 0, 0, 0, time.UTC),
+		End:          time.Date(2024, 1, 19, 21, 0, 0, 0, time.UTC),
+	}}}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Deutscher Parkour Verband//DPV API//DE",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Parkour",
+		"BEGIN:VEVENT",
+		"UID:training-1-cycle-0@example.org",
+		"DTSTAMP:20240101T120000Z",
+		"DTSTART:20240105T180000Z",
+		"DTEND:20240105T200000Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=FR",
+		"EXDATE:20240112T180000Z",
+		"SUMMARY:Training",
+		"GEO:53.500000;10.000000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:training-1-cycle-0@example.org",
+		"DTSTAMP:20240101T120000Z",
+		"RECURRENCE-ID:20240119T180000Z",
+		"DTSTART:20240119T190000Z",
+		"DTEND:20240119T210000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := string(calendar.Bytes()); got != want {
+		t.Errorf("Bytes() = %v, want %v", got, want)
+	}
+}
+
+func Test_writeTimezone(t *testing.T) {
+	zone, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("time zone missing: %v", err)
+	}
+	var b strings.Builder
+	writeTimezone(&b, zone, 2023, 2025)
+	want := strings.Join([]string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20230326T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:20231029T030000",
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"",
+	}, "\r\n")
+	if got := b.String(); got != want {
+		t.Errorf("writeTimezone() = %v, want %v", got, want)
+	}
+}
+
+func TestCalendar_BytesWithTimezone(t *testing.T) {
+	zone, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("time zone missing: %v", err)
+	}
+	calendar := Calendar{Events: []Event{{
+		UID:     "training-1-cycle-0@example.org",
+		Stamp:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Start:   time.Date(2024, 1, 5, 18, 0, 0, 0, zone),
+		End:     time.Date(2024, 1, 5, 20, 0, 0, 0, zone),
+		ExDates: []time.Time{time.Date(2024, 4, 5, 18, 0, 0, 0, zone)},
+	}}}
+	got := string(calendar.Bytes())
+	for _, line := range []string{
+		"TZID:Europe/Berlin\r\n",
+		"DTSTART:20230326T020000\r\n",
+		"DTSTART;TZID=Europe/Berlin:20240105T180000\r\n",
+		"DTEND;TZID=Europe/Berlin:20240105T200000\r\n",
+		"EXDATE;TZID=Europe/Berlin:20240405T180000\r\n",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("Bytes() does not contain %q:\n%s", line, got)
+		}
+	}
+}
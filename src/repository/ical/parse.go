@@ -0,0 +1,255 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"pkv/api/src/repository/t"
+	"strings"
+	"time"
+)
+
+// Property is a content line of an iCalendar object, see RFC 5545 section 3.1
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a block between BEGIN and END lines, such as VCALENDAR or VEVENT
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []Component
+}
+
+// Get returns the first property with the given name
+func (c Component) Get(name string) (Property, bool) {
+	for _, property := range c.Properties {
+		if property.Name == name {
+			return property, true
+		}
+	}
+	return Property{}, false
+}
+
+// Text returns the unescaped value of the first property with the given name
+func (c Component) Text(name string) string {
+	property, _ := c.Get(name)
+	return Unescape(property.Value)
+}
+
+// All returns all properties with the given name
+func (c Component) All(name string) []Property {
+	var properties []Property
+	for _, property := range c.Properties {
+		if property.Name == name {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// Parse reads an iCalendar object and returns its outermost component
+func Parse(data []byte) (Component, error) {
+	var stack []Component
+	var root *Component
+	for n, line := range unfold(data) {
+		if line == "" {
+			continue
+		}
+		property, err := parseLine(line)
+		if err != nil {
+			return Component{}, t.Errorf("line %d: %w", n+1, err)
+		}
+		switch property.Name {
+		case "BEGIN":
+			stack = append(stack, Component{Name: strings.ToUpper(property.Value)})
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return Component{}, t.Errorf("line %d: unexpected END:%s", n+1, property.Value)
+			}
+			component := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				root = &component
+			} else {
+				parent := &stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			}
+		default:
+			if len(stack) == 0 {
+				return Component{}, t.Errorf("line %d: property %s outside of a component", n+1, property.Name)
+			}
+			current := &stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+		if root != nil {
+			break
+		}
+	}
+	if root == nil {
+		return Component{}, t.Errorf("no complete iCalendar object found")
+	}
+	return *root, nil
+}
+
+// unfold joins folded content lines, see RFC 5545 section 3.1
+func unfold(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseLine splits a content line into name, parameters and value, respecting quoted parameter values
+func parseLine(line string) (Property, error) {
+	property := Property{Params: make(map[string]string)}
+	quoted := false
+	start := 0
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == ';' || c == ':':
+			part := line[start:i]
+			if property.Name == "" {
+				property.Name = strings.ToUpper(part)
+			} else {
+				key, value, _ := strings.Cut(part, "=")
+				property.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+			}
+			start = i + 1
+			if c == ':' {
+				property.Value = line[start:]
+				if property.Name == "" {
+					return property, t.Errorf("property name missing")
+				}
+				return property, nil
+			}
+		}
+	}
+	return property, t.Errorf("colon missing in %s", line)
+}
+
+// Unescape reverts Escape
+func Unescape(text string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range text {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ParseTime reads a DATE or DATE-TIME value and returns the wall clock time in the given zone. Values with a TZID
+// that is unknown to the system are read as if they were given in that zone.
+func ParseTime(property Property, zone *time.Location) (time.Time, bool, error) {
+	value := property.Value
+	if property.Params["VALUE"] == "DATE" || len(value) == len(DateFormat) {
+		date, err := time.ParseInLocation(DateFormat, value, zone)
+		if err != nil {
+			return date, true, t.Errorf("invalid date %s: %w", value, err)
+		}
+		return date, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		instant, err := time.ParseInLocation(DateTimeFormat, strings.TrimSuffix(value, "Z"), time.UTC)
+		if err != nil {
+			return instant, false, t.Errorf("invalid date %s: %w", value, err)
+		}
+		return instant.In(zone), false, nil
+	}
+	location := zone
+	if tzid := property.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			location = l
+		}
+	}
+	instant, err := time.ParseInLocation(DateTimeFormat, value, location)
+	if err != nil {
+		return instant, false, t.Errorf("invalid date %s: %w", value, err)
+	}
+	return instant.In(zone), false, nil
+}
+
+// ParseTimes reads a comma separated list of DATE or DATE-TIME values such as in EXDATE and RDATE properties
+func ParseTimes(property Property, zone *time.Location) ([]time.Time, error) {
+	var times []time.Time
+	for _, value := range strings.Split(property.Value, ",") {
+		if value == "" {
+			continue
+		}
+		single := property
+		single.Value = value
+		instant, _, err := ParseTime(single, zone)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, instant)
+	}
+	return times, nil
+}
+
+// ParseDuration reads a DURATION value such as P1DT2H30M, see RFC 5545 section 3.3.6
+func ParseDuration(value string) (time.Duration, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	if rest == value || rest == "" {
+		return 0, t.Errorf("invalid duration %s", value)
+	}
+	var duration time.Duration
+	number := 0
+	digits := false
+	for _, r := range rest {
+		switch {
+		case r >= '0' && r <= '9':
+			number = number*10 + int(r-'0')
+			digits = true
+			continue
+		case r == 'T':
+			continue
+		case !digits:
+			return 0, t.Errorf("invalid duration %s", value)
+		case r == 'W':
+			duration += time.Duration(number) * 7 * 24 * time.Hour
+		case r == 'D':
+			duration += time.Duration(number) * 24 * time.Hour
+		case r == 'H':
+			duration += time.Duration(number) * time.Hour
+		case r == 'M':
+			duration += time.Duration(number) * time.Minute
+		case r == 'S':
+			duration += time.Duration(number) * time.Second
+		default:
+			return 0, t.Errorf("invalid duration %s", value)
+		}
+		number = 0
+		digits = false
+	}
+	if digits || strings.TrimSuffix(rest, "T") == "" {
+		return 0, t.Errorf("invalid duration %s", value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,172 @@
+package ical
+
+import (
+	"pkv/api/src/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240105T180000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20240105T200000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=FR;UNTIL=20240628T160000Z\r\n" +
+	"EXDATE;TZID=Europe/Berlin:20240112T180000\r\n" +
+	"UID:friday@example.org\r\n" +
+	"SUMMARY:Parkour\\, Freitag\r\n" +
+	"DESCRIPTION:Für alle\\nab 12 Jahren. Bitte Schuhe mitbrin\r\n" +
+	" gen.\r\n" +
+	"LOCATION:\"Halle 1\"\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240120T100000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20240120T120000\r\n" +
+	"RECURRENCE-ID;TZID=Europe/Berlin:20240119T180000\r\n" +
+	"UID:friday@example.org\r\n" +
+	"SUMMARY:Parkour\\, Samstag\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20240327T170000Z\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"RRULE:FREQ=MONTHLY;BYDAY=-1WE;INTERVAL=2;COUNT=3\r\n" +
+	"UID:jam@example.org\r\n" +
+	"SUMMARY:Jam\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20240310\r\n" +
+	"UID:once@example.org\r\n" +
+	"SUMMARY:Workshop\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20240304T180000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,TH\r\n" +
+	"UID:twice@example.org\r\n" +
+	"SUMMARY:Zweimal\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrences(t *testing.T) {
+	zone, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data missing: %v", err)
+	}
+	root, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	recurrences, rejections := Recurrences(root, zone)
+	want := []Recurrence{{
+		UID:         "friday@example.org",
+		Summary:     "Parkour, Freitag",
+		Description: "Für alle\nab 12 Jahren. Bitte Schuhe mitbringen.",
+		Location:    `"Halle 1"`,
+		Cycle: domain.Cycle{
+			Weekday:   5,
+			Begin:     18 * 3600,
+			Duration:  2 * 3600,
+			Startdate: date(2024, 1, 5),
+			Enddate:   date(2024, 6, 29),
+		},
+		Exceptions: []domain.Exception{
+			{Date: date(2024, 1, 12)},
+			{Date: date(2024, 1, 19)},
+			{Date: date(2024, 1, 20), Begin: 10 * 3600, Duration: 2 * 3600},
+		},
+	}, {
+		UID:     "jam@example.org",
+		Summary: "Jam",
+		Cycle: domain.Cycle{
+			Weekday:   3,
+			Monthday:  -1,
+			Interval:  2,
+			Begin:     18 * 3600,
+			Duration:  5400,
+			Startdate: date(2024, 3, 27),
+			Enddate:   date(2024, 8, 1),
+		},
+	}, {
+		UID:     "twice@example.org",
+		Summary: "Zweimal",
+		Cycle: domain.Cycle{
+			Weekdays:  []int{1, 4},
+			Begin:     18 * 3600,
+			Startdate: date(2024, 3, 4),
+		},
+	}}
+	if !reflect.DeepEqual(recurrences, want) {
+		t.Errorf("Recurrences() = %+v, want %+v", recurrences, want)
+	}
+	if len(rejections) != 1 || rejections[0].UID != "once@example.org" {
+		t.Errorf("Recurrences() rejected %+v", rejections)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	start := time.Date(2024, 1, 3, 18, 0, 0, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		rule    string
+		want    domain.Cycle
+		wantErr bool
+	}{
+		{"FREQ=DAILY", domain.Cycle{Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=WEEKLY", domain.Cycle{Weekday: 3, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=SU;WKST=MO", domain.Cycle{Weekday: 7, Interval: 2, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=MONTHLY", domain.Cycle{Monthday: 3, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=MONTHLY;BYDAY=1TH", domain.Cycle{Weekday: 4, Monthday: 1, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=MONTHLY;BYDAY=WE;BYSETPOS=-2", domain.Cycle{Weekday: 3, Monthday: -2, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=DAILY;INTERVAL=3;COUNT=4", domain.Cycle{Interval: 3, Startdate: date(2024, 1, 3), Enddate: date(2024, 1, 13)}, false},
+		{"FREQ=WEEKLY;UNTIL=20240131", domain.Cycle{Weekday: 3, Startdate: date(2024, 1, 3), Enddate: date(2024, 2, 1)}, false},
+		{"FREQ=WEEKLY;BYDAY=TH,MO", domain.Cycle{Weekdays: []int{1, 4}, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=MONTHLY;BYDAY=1MO,1TH", domain.Cycle{Weekdays: []int{1, 4}, Monthday: 1, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=YEARLY", domain.Cycle{Monthday: 3, Month: 1, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=YEARLY;BYMONTH=6;BYDAY=1SA", domain.Cycle{Weekday: 6, Monthday: 1, Month: 6, Startdate: date(2024, 1, 3)}, false},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1;COUNT=3", domain.Cycle{Monthday: -1, Month: 2, Startdate: date(2024, 1, 3), Enddate: date(2025, 3, 1)}, false},
+		{"FREQ=YEARLY;BYDAY=1SA", domain.Cycle{}, true},
+		{"FREQ=YEARLY;BYMONTH=6,7;BYDAY=1SA", domain.Cycle{}, true},
+		{"FREQ=MONTHLY;BYDAY=1MO,2TH", domain.Cycle{}, true},
+		{"FREQ=MONTHLY;BYDAY=MO,TH;BYSETPOS=1", domain.Cycle{}, true},
+		{"FREQ=MONTHLY;BYDAY=WE", domain.Cycle{}, true},
+		{"FREQ=DAILY;BYHOUR=10", domain.Cycle{}, true},
+		{"FREQ=DAILY;COUNT=1000000000", domain.Cycle{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := Cycle(tt.rule, start, time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Cycle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycle() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"PT1H30M", 90 * time.Minute, false},
+		{"P1DT2H", 26 * time.Hour, false},
+		{"P1W", 7 * 24 * time.Hour, false},
+		{"PT15S", 15 * time.Second, false},
+		{"1H", 0, true},
+		{"PT", 0, true},
+		{"PT5", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDuration(%s) = %v, %v, want %v, wantErr %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
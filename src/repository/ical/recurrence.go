@@ -0,0 +1,207 @@
+package ical
+
+import (
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"sort"
+	"time"
+)
+
+// Recurrence is a recurring event of an iCalendar object expressed as a cycle with exceptions
+type Recurrence struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Cycle       domain.Cycle
+	Exceptions  []domain.Exception
+}
+
+// Rejection explains why an event cannot be expressed as a Recurrence
+type Rejection struct {
+	UID     string
+	Summary string
+	Err     error
+}
+
+// Recurrences groups the events of a calendar by their UID and converts every group consisting of a recurring event
+// and its overridden instances into a Recurrence. Wall clock times are computed in the given zone.
+func Recurrences(calendar Component, zone *time.Location) ([]Recurrence, []Rejection) {
+	var uids []string
+	groups := make(map[string][]Component)
+	for _, component := range calendar.Components {
+		if component.Name != "VEVENT" {
+			continue
+		}
+		uid := component.Text("UID")
+		if _, ok := groups[uid]; !ok {
+			uids = append(uids, uid)
+		}
+		groups[uid] = append(groups[uid], component)
+	}
+	var recurrences []Recurrence
+	var rejections []Rejection
+	for _, uid := range uids {
+		recurrence, err := recurrence(groups[uid], zone)
+		if err != nil {
+			rejections = append(rejections, Rejection{UID: uid, Summary: recurrence.Summary, Err: err})
+			continue
+		}
+		recurrences = append(recurrences, recurrence)
+	}
+	return recurrences, rejections
+}
+
+func recurrence(events []Component, zone *time.Location) (Recurrence, error) {
+	var master *Component
+	var overrides []Component
+	for i, event := range events {
+		if _, ok := event.Get("RECURRENCE-ID"); ok {
+			overrides = append(overrides, event)
+			continue
+		}
+		if master != nil {
+			return Recurrence{Summary: event.Text("SUMMARY")}, t.Errorf("the UID is used by several events")
+		}
+		master = &events[i]
+	}
+	if master == nil {
+		return Recurrence{Summary: events[0].Text("SUMMARY")}, t.Errorf("the event does not recur")
+	}
+	recurrence := Recurrence{
+		UID:         master.Text("UID"),
+		Summary:     master.Text("SUMMARY"),
+		Description: master.Text("DESCRIPTION"),
+		Location:    master.Text("LOCATION"),
+	}
+	if recurrence.UID == "" {
+		return recurrence, t.Errorf("the event has no UID")
+	}
+	if master.Text("STATUS") == "CANCELLED" {
+		return recurrence, t.Errorf("the event is cancelled")
+	}
+	rules := master.All("RRULE")
+	if len(rules) == 0 {
+		return recurrence, t.Errorf("the event does not recur")
+	}
+	if len(rules) > 1 || len(master.All("EXRULE")) > 0 {
+		return recurrence, t.Errorf("events with several recurrence rules are not supported")
+	}
+	start, begin, duration, err := timing(*master, zone)
+	if err != nil {
+		return recurrence, err
+	}
+	cycle, err := Cycle(rules[0].Value, start, zone)
+	if err != nil {
+		return recurrence, err
+	}
+	cycle.Begin = begin
+	cycle.Duration = duration
+	recurrence.Cycle = cycle
+
+	var exceptions []domain.Exception
+	for _, property := range master.All("EXDATE") {
+		dates, err := ParseTimes(property, zone)
+		if err != nil {
+			return recurrence, err
+		}
+		for _, date := range dates {
+			exceptions = append(exceptions, domain.Exception{Date: Date(date)})
+		}
+	}
+	for _, property := range master.All("RDATE") {
+		dates, err := ParseTimes(property, zone)
+		if err != nil {
+			return recurrence, err
+		}
+		for _, date := range dates {
+			exception := domain.Exception{Date: Date(date), Begin: cycle.Begin, Duration: cycle.Duration}
+			if property.Params["VALUE"] != "DATE" {
+				exception.Begin = secondsOfDay(date)
+			}
+			exceptions = append(exceptions, exception)
+		}
+	}
+	for _, override := range overrides {
+		property, _ := override.Get("RECURRENCE-ID")
+		original, _, err := ParseTime(property, zone)
+		if err != nil {
+			return recurrence, err
+		}
+		exceptions = append(exceptions, domain.Exception{Date: Date(original)})
+		if override.Text("STATUS") == "CANCELLED" {
+			continue
+		}
+		start, begin, duration, err := timing(override, zone)
+		if err != nil {
+			return recurrence, err
+		}
+		exceptions = append(exceptions, domain.Exception{Date: Date(start), Begin: begin, Duration: duration})
+	}
+	recurrence.Exceptions = mergeExceptions(exceptions)
+	return recurrence, nil
+}
+
+// timing reads the start of an event as wall clock time, as well as the beginning and the duration in seconds
+func timing(event Component, zone *time.Location) (time.Time, int, int, error) {
+	property, ok := event.Get("DTSTART")
+	if !ok {
+		return time.Time{}, 0, 0, t.Errorf("the event has no start")
+	}
+	start, allDay, err := ParseTime(property, zone)
+	if err != nil {
+		return start, 0, 0, err
+	}
+	var end time.Time
+	if property, ok := event.Get("DTEND"); ok {
+		if end, _, err = ParseTime(property, zone); err != nil {
+			return start, 0, 0, err
+		}
+	} else if property, ok := event.Get("DURATION"); ok {
+		duration, err := ParseDuration(property.Value)
+		if err != nil {
+			return start, 0, 0, err
+		}
+		end = start.Add(duration)
+	} else if allDay {
+		end = start.AddDate(0, 0, 1)
+	} else {
+		end = start
+	}
+	if end.Before(start) {
+		return start, 0, 0, t.Errorf("the event ends before it starts")
+	}
+	return start, secondsOfDay(start), int(end.Sub(start).Seconds()), nil
+}
+
+func secondsOfDay(wallClock time.Time) int {
+	return wallClock.Hour()*3600 + wallClock.Minute()*60 + wallClock.Second()
+}
+
+// mergeExceptions removes cancellations of days that have another exception anyway and sorts the exceptions
+func mergeExceptions(exceptions []domain.Exception) []domain.Exception {
+	replaced := make(map[time.Time]bool)
+	for _, exception := range exceptions {
+		if exception.Duration > 0 {
+			replaced[exception.Date] = true
+		}
+	}
+	var merged []domain.Exception
+	cancelled := make(map[time.Time]bool)
+	for _, exception := range exceptions {
+		if exception.Duration == 0 {
+			if replaced[exception.Date] || cancelled[exception.Date] {
+				continue
+			}
+			cancelled[exception.Date] = true
+		}
+		merged = append(merged, exception)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Date.Equal(merged[j].Date) {
+			return merged[i].Begin < merged[j].Begin
+		}
+		return merged[i].Date.Before(merged[j].Date)
+	})
+	return merged
+}
@@ -0,0 +1,283 @@
+package ical
+
+import (
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/t"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCount limits the COUNT of a rule, which is converted into an end date by stepping through the occurrences
+const maxCount = 10000
+
+// Weekdays maps Cycle.Weekday (1 = Monday, 7 = Sunday) to the weekday codes used by RRULE
+var Weekdays = []string{"", "MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// RRule turns a cycle into a recurrence rule as per RFC 5545 section 3.3.10. Cycles are anchored at the first
+// occurrence, which has to be used as DTSTART. The end date is converted to UTC using the time zone of DTSTART.
+func RRule(cycle domain.Cycle, zone *time.Location) (string, error) {
+	if err := calendar.ValidateCycle(cycle); err != nil {
+		return "", err
+	}
+	weekdays := calendar.Weekdays(cycle)
+	byDay := func(ordinal string) string {
+		codes := make([]string, len(weekdays))
+		for i, weekday := range weekdays {
+			codes[i] = ordinal + Weekdays[weekday]
+		}
+		return "BYDAY=" + strings.Join(codes, ",")
+	}
+	monthday := strconv.Itoa(cycle.Monthday)
+	var parts []string
+	switch {
+	case cycle.Month != 0 && len(weekdays) == 0:
+		parts = append(parts, "FREQ=YEARLY", "BYMONTH="+strconv.Itoa(cycle.Month), "BYMONTHDAY="+monthday)
+	case cycle.Month != 0:
+		parts = append(parts, "FREQ=YEARLY", "BYMONTH="+strconv.Itoa(cycle.Month), byDay(monthday))
+	case cycle.Monthday == 0 && len(weekdays) == 0:
+		parts = append(parts, "FREQ=DAILY")
+	case cycle.Monthday == 0:
+		parts = append(parts, "FREQ=WEEKLY", byDay(""))
+	case len(weekdays) == 0:
+		parts = append(parts, "FREQ=MONTHLY", "BYMONTHDAY="+monthday)
+	case len(weekdays) == 1:
+		parts = append(parts, "FREQ=MONTHLY", byDay(""), "BYSETPOS="+monthday)
+	default:
+		parts = append(parts, "FREQ=MONTHLY", byDay(monthday))
+	}
+	if cycle.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(cycle.Interval))
+	}
+	if !cycle.Enddate.IsZero() {
+		// the end date is exclusive, UNTIL is inclusive
+		until := calendar.Instant(cycle.Enddate, 0, zone).Add(-time.Second)
+		parts = append(parts, "UNTIL="+until.UTC().Format(DateTimeFormat)+"Z")
+	}
+	return strings.Join(parts, ";"), nil
+}
+
+// Cycle turns a recurrence rule of an event starting at the given wall clock time into a cycle. It is the inverse of
+// RRule and fails for rules that cannot be expressed by the cycle model. A COUNT of up to maxCount is converted into an
+// end date.
+func Cycle(rule string, start time.Time, zone *time.Location) (domain.Cycle, error) {
+	cycle := domain.Cycle{Startdate: Date(start)}
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(part, "=")
+		parts[strings.ToUpper(name)] = strings.ToUpper(value)
+	}
+	var err error
+	if value, ok := parts["INTERVAL"]; ok {
+		if cycle.Interval, err = strconv.Atoi(value); err != nil || cycle.Interval < 1 {
+			return cycle, t.Errorf("invalid interval %s", value)
+		}
+		if cycle.Interval == 1 {
+			cycle.Interval = 0
+		}
+	}
+	byDay, hasByDay := parts["BYDAY"]
+	_, hasBySetPos := parts["BYSETPOS"]
+	_, hasByMonthDay := parts["BYMONTHDAY"]
+	byMonth, hasByMonth := parts["BYMONTH"]
+	switch parts["FREQ"] {
+	case "DAILY":
+		if hasByDay || hasByMonthDay || hasBySetPos || hasByMonth {
+			return cycle, t.Errorf("daily rules cannot be restricted to certain days")
+		}
+	case "WEEKLY":
+		if hasByMonthDay || hasBySetPos || hasByMonth {
+			return cycle, t.Errorf("weekly rules cannot be restricted to certain days of the month")
+		}
+		cycle.Weekday = isoWeekday(start)
+		if hasByDay {
+			ordinal, weekdays, err := parseByDay(byDay)
+			if err != nil {
+				return cycle, err
+			}
+			if ordinal != 0 {
+				return cycle, t.Errorf("weekly rules cannot refer to the n-th weekday")
+			}
+			setWeekdays(&cycle, weekdays)
+		}
+	case "MONTHLY":
+		if hasByMonth {
+			return cycle, t.Errorf("monthly rules cannot be restricted to certain months")
+		}
+		if err := dayOfMonth(&cycle, parts, start); err != nil {
+			return cycle, err
+		}
+	case "YEARLY":
+		cycle.Month = int(start.Month())
+		if hasByMonth {
+			if cycle.Month, err = strconv.Atoi(byMonth); err != nil || cycle.Month < 1 || cycle.Month > 12 {
+				return cycle, t.Errorf("yearly rules need to refer to a single month")
+			}
+		} else if hasByDay || hasByMonthDay {
+			return cycle, t.Errorf("yearly rules need to refer to a single month")
+		}
+		if err := dayOfMonth(&cycle, parts, start); err != nil {
+			return cycle, err
+		}
+	default:
+		return cycle, t.Errorf("frequency %s is not supported", parts["FREQ"])
+	}
+	for name := range parts {
+		switch name {
+		case "FREQ", "INTERVAL", "BYDAY", "BYSETPOS", "BYMONTHDAY", "BYMONTH", "UNTIL", "COUNT", "WKST":
+		default:
+			return cycle, t.Errorf("recurrence rule part %s is not supported", name)
+		}
+	}
+	if value, ok := parts["WKST"]; ok && value != "MO" && cycle.Interval > 1 && len(cycle.Weekdays) > 0 {
+		return cycle, t.Errorf("weeks need to start on Monday")
+	}
+	if value, ok := parts["UNTIL"]; ok {
+		until, _, err := ParseTime(Property{Value: value}, zone)
+		if err != nil {
+			return cycle, err
+		}
+		// UNTIL is inclusive, the end date is not
+		cycle.Enddate = Date(until).AddDate(0, 0, 1)
+	}
+	if value, ok := parts["COUNT"]; ok {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 {
+			return cycle, t.Errorf("invalid count %s", value)
+		}
+		if count > maxCount {
+			return cycle, t.Errorf("count %d exceeds the limit of %d occurrences", count, maxCount)
+		}
+		last := cycle.Startdate
+		for i := 1; i < count; i++ {
+			last = calendar.ComputeNextSatisfyingDate(cycle, last)
+		}
+		cycle.Enddate = last.AddDate(0, 0, 1)
+	}
+	return cycle, nil
+}
+
+// dayOfMonth reads the day of a monthly or yearly rule, which defaults to the day of the start
+func dayOfMonth(cycle *domain.Cycle, parts map[string]string, start time.Time) error {
+	byDay, hasByDay := parts["BYDAY"]
+	bySetPos, hasBySetPos := parts["BYSETPOS"]
+	byMonthDay, hasByMonthDay := parts["BYMONTHDAY"]
+	var err error
+	switch {
+	case hasByDay && hasByMonthDay:
+		return t.Errorf("rules cannot combine weekdays and days of the month")
+	case hasByDay:
+		ordinal, weekdays, err := parseByDay(byDay)
+		if err != nil {
+			return err
+		}
+		if hasBySetPos {
+			if ordinal != 0 || len(weekdays) > 1 {
+				return t.Errorf("BYSETPOS is only supported with a single weekday")
+			}
+			if ordinal, err = parseOrdinal(bySetPos); err != nil {
+				return err
+			}
+		}
+		if ordinal == 0 {
+			return t.Errorf("rules need to refer to the n-th weekday of the month")
+		}
+		setWeekdays(cycle, weekdays)
+		cycle.Monthday = ordinal
+	case hasBySetPos:
+		return t.Errorf("BYSETPOS requires BYDAY")
+	case hasByMonthDay:
+		if cycle.Monthday, err = parseOrdinal(byMonthDay); err != nil {
+			return err
+		}
+	default:
+		cycle.Monthday = start.Day()
+	}
+	return nil
+}
+
+// setWeekdays stores a single weekday as Weekday and several ones as Weekdays
+func setWeekdays(cycle *domain.Cycle, weekdays []int) {
+	cycle.Weekday = 0
+	cycle.Weekdays = nil
+	if len(weekdays) == 1 {
+		cycle.Weekday = weekdays[0]
+		return
+	}
+	cycle.Weekdays = weekdays
+}
+
+// Date returns the date of a wall clock time as UTC midnight, which is how dates of cycles and exceptions are stored
+func Date(wallClock time.Time) time.Time {
+	return time.Date(wallClock.Year(), wallClock.Month(), wallClock.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// isoWeekday returns the weekday of a time with 1 = Monday and 7 = Sunday
+func isoWeekday(date time.Time) int {
+	if date.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(date.Weekday())
+}
+
+// parseByDay reads a list of BYDAY entries such as WE, 2TH or MO,TH. All entries need to have the same ordinal.
+func parseByDay(value string) (int, []int, error) {
+	var weekdays []int
+	ordinal := 0
+	for i, entry := range strings.Split(value, ",") {
+		entryOrdinal, weekday, err := parseWeekday(entry)
+		if err != nil {
+			return 0, nil, err
+		}
+		if i > 0 && entryOrdinal != ordinal {
+			return 0, nil, t.Errorf("rules with different ordinals per weekday are not supported")
+		}
+		ordinal = entryOrdinal
+		if !slices.Contains(weekdays, weekday) {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	slices.Sort(weekdays)
+	return ordinal, weekdays, nil
+}
+
+// parseWeekday reads a single BYDAY entry such as WE, 2TH or -1SU
+func parseWeekday(value string) (int, int, error) {
+	if len(value) < 2 {
+		return 0, 0, t.Errorf("invalid weekday %s", value)
+	}
+	weekday := 0
+	for i, code := range Weekdays {
+		if code != "" && code == value[len(value)-2:] {
+			weekday = i
+		}
+	}
+	if weekday == 0 {
+		return 0, 0, t.Errorf("invalid weekday %s", value)
+	}
+	ordinal := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		var err error
+		if ordinal, err = parseOrdinal(prefix); err != nil {
+			return 0, 0, err
+		}
+	}
+	return ordinal, weekday, nil
+}
+
+// parseOrdinal reads a single non-zero number such as 2 or -1
+func parseOrdinal(value string) (int, error) {
+	if strings.Contains(value, ",") {
+		return 0, t.Errorf("rules with several days are not supported")
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+	if err != nil || ordinal == 0 {
+		return 0, t.Errorf("invalid ordinal %s", value)
+	}
+	return ordinal, nil
+}
@@ -0,0 +1,160 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// transition is a change of the UTC offset of a time zone
+type transition struct {
+	at         time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	dst        bool
+}
+
+// observance groups transitions to the same offset, which become a STANDARD or DAYLIGHT component
+type observance struct {
+	transitions []transition
+}
+
+// writeTimezone writes a VTIMEZONE component for the zone, see RFC 5545 section 3.6.5. The transitions between the
+// given years are probed, and those recurring every year on the same weekday are written as RRULE, so calendar
+// clients can rely on them beyond the last year.
+func writeTimezone(b *strings.Builder, zone *time.Location, fromYear, toYear int) {
+	writeLine(b, "BEGIN:VTIMEZONE")
+	writeLine(b, "TZID:"+zone.String())
+	start := time.Date(fromYear, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(toYear+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	transitions := probeTransitions(zone, start, end)
+	if len(transitions) == 0 {
+		name, offset := start.In(zone).Zone()
+		writeObservance(b, "STANDARD", "19700101T000000", nil, offset, offset, name)
+	}
+	var keys []string
+	observances := make(map[string]*observance)
+	for _, transition := range transitions {
+		key := fmt.Sprintf("%d %d %s %t", transition.offsetFrom, transition.offsetTo, transition.name, transition.dst)
+		if _, ok := observances[key]; !ok {
+			keys = append(keys, key)
+			observances[key] = &observance{}
+		}
+		observances[key].transitions = append(observances[key].transitions, transition)
+	}
+	for _, key := range keys {
+		o := observances[key]
+		first := o.transitions[0]
+		component := "STANDARD"
+		if first.dst {
+			component = "DAYLIGHT"
+		}
+		var lines []string
+		if rule := o.yearlyRule(); rule != "" {
+			lines = append(lines, "RRULE:"+rule)
+		} else {
+			for _, transition := range o.transitions[1:] {
+				lines = append(lines, "RDATE:"+transition.local().Format(DateTimeFormat))
+			}
+		}
+		writeObservance(b, component, first.local().Format(DateTimeFormat), lines, first.offsetFrom, first.offsetTo, first.name)
+	}
+	writeLine(b, "END:VTIMEZONE")
+}
+
+func writeObservance(b *strings.Builder, component string, start string, lines []string, offsetFrom, offsetTo int, name string) {
+	writeLine(b, "BEGIN:"+component)
+	writeLine(b, "DTSTART:"+start)
+	for _, line := range lines {
+		writeLine(b, line)
+	}
+	writeLine(b, "TZOFFSETFROM:"+formatOffset(offsetFrom))
+	writeLine(b, "TZOFFSETTO:"+formatOffset(offsetTo))
+	if name != "" {
+		writeLine(b, "TZNAME:"+Escape(name))
+	}
+	writeLine(b, "END:"+component)
+}
+
+// probeTransitions finds the instants within [start, end) at which the UTC offset of the zone changes
+func probeTransitions(zone *time.Location, start, end time.Time) []transition {
+	var transitions []transition
+	_, offset := start.In(zone).Zone()
+	previous := start
+	for current := start.Add(24 * time.Hour); current.Before(end); current = current.Add(24 * time.Hour) {
+		if _, currentOffset := current.In(zone).Zone(); currentOffset != offset {
+			// the offset changed within the last day, so find the second at which it did
+			at := previous.Add(time.Duration(sort.Search(24*60*60, func(seconds int) bool {
+				_, o := previous.Add(time.Duration(seconds) * time.Second).In(zone).Zone()
+				return o != offset
+			})) * time.Second)
+			name, _ := at.In(zone).Zone()
+			transitions = append(transitions, transition{at, offset, currentOffset, name, at.In(zone).IsDST()})
+			offset = currentOffset
+		}
+		previous = current
+	}
+	return transitions
+}
+
+// local returns the wall clock time right before the transition, which is how DTSTART of observances is given
+func (t transition) local() time.Time {
+	return t.at.In(time.FixedZone("", t.offsetFrom))
+}
+
+// yearlyRule returns an RRULE if the transitions take place every year on the n-th or last weekday of the same
+// month at the same time, or an empty string otherwise
+func (o observance) yearlyRule() string {
+	if len(o.transitions) < 2 {
+		return ""
+	}
+	first := o.transitions[0].local()
+	candidates := ordinals(first)
+	for i, transition := range o.transitions[1:] {
+		local := transition.local()
+		if local.Year() != first.Year()+i+1 || local.Month() != first.Month() || local.Weekday() != first.Weekday() ||
+			local.Hour() != first.Hour() || local.Minute() != first.Minute() || local.Second() != first.Second() {
+			return ""
+		}
+		var common []int
+		for _, ordinal := range ordinals(local) {
+			for _, candidate := range candidates {
+				if ordinal == candidate {
+					common = append(common, ordinal)
+				}
+			}
+		}
+		candidates = common
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	weekday := Weekdays[isoWeekday(first)]
+	return "FREQ=YEARLY;BYMONTH=" + strconv.Itoa(int(first.Month())) + ";BYDAY=" + strconv.Itoa(candidates[0]) + weekday
+}
+
+// ordinals returns n if the date is the n-th weekday of its month, and -1 as well if it is the last one
+func ordinals(date time.Time) []int {
+	ordinals := []int{(date.Day()-1)/7 + 1}
+	if date.AddDate(0, 0, 7).Month() != date.Month() {
+		ordinals = append(ordinals, -1)
+	}
+	return ordinals
+}
+
+// formatOffset formats a UTC offset in seconds as per RFC 5545 section 3.3.14
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	formatted := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+	if offset%60 != 0 {
+		formatted += fmt.Sprintf("%02d", offset%60)
+	}
+	return formatted
+}
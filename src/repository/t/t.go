@@ -1,44 +1,236 @@
 package t
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"pkv/api/src/repository/dpv"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-func T(text string) string {
-	val, ok := de[text]
-	if ok {
-		return val
+// Source is the language of the messages in the code, which need no catalogue
+const Source = "en"
+
+// catalogues maps languages to their translations of the messages, which map plural categories to the forms of the
+// translated message. Messages without plural forms only have the category "other".
+var catalogues = map[string]map[string]map[string]string{}
+
+// pluralKey matches catalogue keys ending with a plural category, e.g. "%d days[one]"
+var pluralKey = regexp.MustCompile(`^(.*)\[(zero|one|two|few|many|other)]$`)
+
+// T translates a message into the language, choosing the plural form for the count, see Chain for the fallbacks
+func T(language string, text string, count ...int) string {
+	for _, l := range Chain(language) {
+		forms, ok := catalogues[l][text]
+		if !ok {
+			continue
+		}
+		if len(count) > 0 {
+			if form, ok := forms[PluralCategory(l, count[0])]; ok {
+				return form
+			}
+		}
+		if form, ok := forms["other"]; ok {
+			return form
+		}
 	}
 	return text
 }
 
+// Chain returns the languages whose catalogues are searched for a message in the given language: the language itself,
+// its base language without region and English, e.g. de-AT, de and en
+func Chain(language string) []string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	var chain []string
+	if language != "" {
+		chain = append(chain, language)
+	}
+	if base, _, found := strings.Cut(language, "-"); found && base != "" {
+		chain = append(chain, base)
+	}
+	if language != Source {
+		chain = append(chain, Source)
+	}
+	return chain
+}
+
+// Supported returns whether messages can be shown in the language, because it or its base language is the source
+// language or has a catalogue
+func Supported(language string) bool {
+	language = strings.ToLower(strings.TrimSpace(language))
+	base, _, _ := strings.Cut(language, "-")
+	_, ok := catalogues[language]
+	_, baseOk := catalogues[base]
+	return base == Source || ok || baseOk
+}
+
+// Preferences returns the language tags of an Accept-Language header, most preferred first, without wildcards and
+// languages with a quality of zero
+func Preferences(header string) []string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if tag != "" && tag != "*" && quality > 0 {
+			preferences = append(preferences, preference{tag, quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+	tags := make([]string, 0, len(preferences))
+	for _, p := range preferences {
+		tags = append(tags, p.tag)
+	}
+	return tags
+}
+
+// Message is an error whose text is translated when it is shown to a client, see Localise. Error returns the text in
+// the source language, which is used for logging.
+type Message struct {
+	format string
+	args   []any
+	err    error
+}
+
 func Errorf(format string, a ...any) error {
-	return fmt.Errorf(T(format), a...)
+	return &Message{format, a, fmt.Errorf(format, a...)}
+}
+
+func (m *Message) Error() string {
+	return m.err.Error()
+}
+
+// Unwrap returns the errors wrapped by %w, so that errors.Is and errors.As see through messages
+func (m *Message) Unwrap() []error {
+	switch err := m.err.(type) {
+	case interface{ Unwrap() error }:
+		return []error{err.Unwrap()}
+	case interface{ Unwrap() []error }:
+		return err.Unwrap()
+	}
+	return nil
+}
+
+// In returns the text of the message in the language. Wrapped messages are translated as well, the plural form is
+// chosen for the first integer argument.
+func (m *Message) In(language string) string {
+	args := make([]any, len(m.args))
+	var count []int
+	for i, arg := range m.args {
+		if err, ok := arg.(error); ok {
+			args[i] = localised(Localise(err, language))
+			continue
+		}
+		args[i] = arg
+		if value := reflect.ValueOf(arg); len(count) == 0 && value.CanInt() {
+			count = append(count, int(value.Int()))
+		}
+	}
+	return fmt.Errorf(T(language, m.format, count...), args...).Error()
+}
+
+// Localise returns the text of the error in the language if it is a message, otherwise the text of the error
+func Localise(err error, language string) string {
+	if m, ok := err.(*Message); ok {
+		return m.In(language)
+	}
+	return err.Error()
+}
+
+// localised is a translated error text which replaces a wrapped error when translating a message
+type localised string
+
+func (l localised) Error() string {
+	return string(l)
 }
 
-var de = map[string]string{}
+// PluralCategory returns the CLDR plural category of the count in the language, simplified to integers
+func PluralCategory(language string, n int) string {
+	base, _, _ := strings.Cut(language, "-")
+	switch base {
+	case "zh", "ja", "ko":
+		return "other"
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+	case "ru", "uk":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
 
-func LoadDE(config *dpv.Config) error {
+// Load reads the catalogue strings_<language>.ini of every configured language that has one. Catalogues contain one
+// message per line, followed by an equals sign and its translation. Plural forms are given by appending the plural
+// category in square brackets to the message.
+func Load(config *dpv.Config) error {
 	if config == nil {
 		return fmt.Errorf("config is not initialized")
 	}
-	path := config.Path + "strings_de.ini"
-	bytes, err := os.ReadFile(path)
-	if err != nil {
-		wd, _ := os.Getwd()
-		return fmt.Errorf("could not load strings_de.ini, looking for %v in %v: %w", path, wd, err)
+	var problems []error
+	for _, language := range append([]dpv.Language{{Key: Source}}, config.Settings.Languages...) {
+		path := config.Path + "strings_" + language.Key + ".ini"
+		bytes, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			problems = append(problems, fmt.Errorf("could not load %v: %w", path, err))
+			continue
+		}
+		catalogue, err := parse(string(bytes))
+		if err != nil {
+			problems = append(problems, fmt.Errorf("could not load %v: %w", path, err))
+			continue
+		}
+		catalogues[strings.ToLower(language.Key)] = catalogue
 	}
-	for _, s := range strings.Split(string(bytes), "\n") {
+	return errors.Join(problems...)
+}
+
+func parse(content string) (map[string]map[string]string, error) {
+	catalogue := map[string]map[string]string{}
+	for _, s := range strings.Split(content, "\n") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
 		arr := strings.Split(s, "=")
 		if len(arr) != 2 {
-			return fmt.Errorf("entry contains not exactly one equals sign: %v", s)
+			return nil, fmt.Errorf("entry contains not exactly one equals sign: %v", s)
 		}
-		if len(arr[1]) > 0 {
-			de[arr[0]] = arr[1]
+		if len(arr[1]) == 0 {
+			continue
 		}
+		key, category := arr[0], "other"
+		if match := pluralKey.FindStringSubmatch(key); match != nil {
+			key, category = match[1], match[2]
+		}
+		if catalogue[key] == nil {
+			catalogue[key] = map[string]string{}
+		}
+		catalogue[key][category] = arr[1]
 	}
-	return nil
+	return catalogue, nil
 }
@@ -0,0 +1,78 @@
+package t
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestMessage_In(t *testing.T) {
+	var err error
+	catalogues["de"], err = parse("reading user failed: %w=Lesen des Benutzers fehlgeschlagen: %w\n" +
+		"user %s not found=Benutzer %s nicht gefunden\n" +
+		"%d days left=noch %d Tage\n" +
+		"%d days left[one]=noch %d Tag\n")
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	catalogues["ru"], _ = parse("%d days left[one]=остался %d день\n%d days left[few]=осталось %d дня\n%d days left=осталось %d дней")
+	defer delete(catalogues, "de")
+	defer delete(catalogues, "ru")
+	message := Errorf("reading user failed: %w", Errorf("user %s not found", "jan"))
+	tests := []struct {
+		name     string
+		err      error
+		language string
+		want     string
+	}{
+		{"source", message, "en", "reading user failed: user jan not found"},
+		{"wrapped", message, "de", "Lesen des Benutzers fehlgeschlagen: Benutzer jan nicht gefunden"},
+		{"region", message, "de-AT", "Lesen des Benutzers fehlgeschlagen: Benutzer jan nicht gefunden"},
+		{"without catalogue", message, "fr", "reading user failed: user jan not found"},
+		{"singular", Errorf("%d days left", 1), "de", "noch 1 Tag"},
+		{"plural", Errorf("%d days left", 3), "de", "noch 3 Tage"},
+		{"few", Errorf("%d days left", 23), "ru", "осталось 23 дня"},
+		{"many", Errorf("%d days left", 11), "ru", "осталось 11 дней"},
+		{"other error", io.EOF, "de", "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Localise(tt.err, tt.language); got != tt.want {
+				t.Errorf("Localise() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if message.Error() != "reading user failed: user jan not found" {
+		t.Errorf("Error() = %q, want the source language", message.Error())
+	}
+	if !errors.Is(Errorf("reading failed: %w", io.EOF), io.EOF) {
+		t.Errorf("errors.Is() = false for a wrapped error")
+	}
+}
+
+func TestChain(t *testing.T) {
+	if got := Chain("de-AT"); !reflect.DeepEqual(got, []string{"de-at", "de", "en"}) {
+		t.Errorf("Chain() = %v", got)
+	}
+	if got := Chain("en"); !reflect.DeepEqual(got, []string{"en"}) {
+		t.Errorf("Chain() = %v", got)
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	got := Preferences("fr-CH, fr;q=0.9, en;q=0.8, de;q=0.95, *;q=0.5, it;q=0")
+	if want := []string{"fr-ch", "de", "fr", "en"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Preferences() = %v, want %v", got, want)
+	}
+}
+
+func Test_parse(t *testing.T) {
+	if _, err := parse("a=b=c"); err == nil {
+		t.Errorf("parse() error = nil for two equals signs")
+	}
+	got, err := parse("a=\nb=c\n\nd[other]=e")
+	if want := map[string]map[string]string{"b": {"other": "c"}, "d": {"other": "e"}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("parse() = %v, %v, want %v", got, err, want)
+	}
+}
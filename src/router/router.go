@@ -9,12 +9,15 @@ import (
 	"pkv/api/src/domain"
 	"pkv/api/src/endpoints/accounting"
 	"pkv/api/src/endpoints/authentication"
+	"pkv/api/src/endpoints/comment"
 	"pkv/api/src/endpoints/crud"
 	"pkv/api/src/endpoints/location"
 	"pkv/api/src/endpoints/openai"
 	"pkv/api/src/endpoints/photo"
 	"pkv/api/src/endpoints/query"
+	"pkv/api/src/endpoints/search"
 	"pkv/api/src/endpoints/server"
+	"pkv/api/src/endpoints/training"
 	"pkv/api/src/endpoints/user"
 	"pkv/api/src/endpoints/verband"
 	"pkv/api/src/repository/dpv"
@@ -22,10 +25,16 @@ import (
 	"pkv/api/src/repository/t"
 	accountingService "pkv/api/src/service/accounting"
 	"pkv/api/src/service/captcha"
+	commentService "pkv/api/src/service/comment"
+	"pkv/api/src/service/description"
 	photoService "pkv/api/src/service/photo"
+	searchService "pkv/api/src/service/search"
 	serverService "pkv/api/src/service/server"
+	trainingService "pkv/api/src/service/training"
+	translationService "pkv/api/src/service/translation"
 	userService "pkv/api/src/service/user"
 	verbandService "pkv/api/src/service/verband"
+	"strings"
 	"time"
 )
 
@@ -49,24 +58,42 @@ func NewServer(configPath string, test bool) *http.Server {
 	}
 	dpv.ConfigInstance = config
 
-	trainingCrudHandler := crud.NewHandler[*domain.Training](db, db.Trainings)
-	locationCrudHandler := crud.NewHandler[*domain.Location](db, db.Locations)
-	userCrudHandler := crud.NewHandler[*domain.User](db, db.Users)
-	pageCrudHandler := crud.NewHandler[*domain.Page](db, db.Pages)
+	translator, err := description.NewTranslator(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	translations := translationService.NewService(db, translator)
+	trainings := trainingService.NewService(db, translations)
+	trainingCrudHandler := crud.NewHandler[*domain.Training](db, db.Trainings, description.RenderHook[*domain.Training](db), translationService.EditHook[*domain.Training](db.Trainings), trainingService.ValidateHook, trainings.ConflictHook).OnWrite(trainings.MaterialiseHook, translationService.Hook[*domain.Training](translations, "trainings"))
+	if !test {
+		go trainings.KeepOccurrencesFresh(6 * time.Hour)
+		go translations.KeepTranslating(time.Minute)
+	}
+	locationCrudHandler := crud.NewHandler[*domain.Location](db, db.Locations, description.RenderHook[*domain.Location](db), translationService.EditHook[*domain.Location](db.Locations)).OnWrite(translationService.Hook[*domain.Location](translations, "locations"))
+	userCrudHandler := crud.NewHandler[*domain.User](db, db.Users, description.RenderHook[*domain.User](db), translationService.EditHook[*domain.User](db.Users)).OnWrite(translationService.Hook[*domain.User](translations, "users"))
+	pageCrudHandler := crud.NewHandler[*domain.Page](db, db.Pages, description.RenderHook[*domain.Page](db), translationService.EditHook[*domain.Page](db.Pages)).OnWrite(translationService.Hook[*domain.Page](translations, "pages"))
+	eventCrudHandler := crud.NewHandler[*domain.Event](db, db.Events, description.RenderHook[*domain.Event](db), translationService.EditHook[*domain.Event](db.Events), trainingService.ValidateEventHook).OnWrite(translationService.Hook[*domain.Event](translations, "events"))
 
 	captchaService := captcha.NewService()
 
 	userService := userService.NewService(db)
 	authenticationHandler := authentication.NewHandler(db, userService)
 	queryHandler := query.NewHandler(db)
+	searchHandler := search.NewHandler(searchService.NewService(db))
+	trainingHandler := training.NewHandler(db, trainings)
 	userHandler := user.NewHandler(db, userService)
 	userPhotoHandler := photo.NewPhotoEntityHandler[*domain.User](photoService.NewService(), db.Users)
+	userCommentHandler := comment.NewHandler(db, commentService.NewService(db, db.Users))
+	trainingCommentHandler := comment.NewHandler(db, commentService.NewService(db, db.Trainings))
+	locationCommentHandler := comment.NewHandler(db, commentService.NewService(db, db.Locations))
+	pageCommentHandler := comment.NewHandler(db, commentService.NewService(db, db.Pages))
+	eventCommentHandler := comment.NewHandler(db, commentService.NewService(db, db.Events))
 
 	photoService := photoService.NewService()
 
 	serverHandler := server.NewHandler(serverService.NewService())
 	photoHandler := photo.NewHandler(photoService)
-	locationHandler := location.NewHandler(db, photoService, db.Locations)
+	locationHandler := location.NewHandler(db, photoService, translations, db.Locations)
 
 	accountingHandler := accounting.NewHandler(accountingService.NewService())
 	verbandHandler := verband.NewHandler(verbandService.NewService(), captchaService)
@@ -107,12 +134,40 @@ func NewServer(configPath string, test bool) *http.Server {
 	r.PUT("/api/admin/page", pageCrudHandler.Update)
 	r.DELETE("/api/admin/page/:key", pageCrudHandler.Delete)
 
+	r.POST("/api/admin/event", eventCrudHandler.Create)
+	r.GET("/api/admin/event/:key", eventCrudHandler.Read)
+	r.PUT("/api/admin/event", eventCrudHandler.Update)
+	r.DELETE("/api/admin/event/:key", eventCrudHandler.Delete)
+	r.PUT("/api/admin/event/:key/locations", trainingHandler.PutEventLocations)
+
+	r.GET("/api/admin/conflicts", trainingHandler.GetConflicts)
+
 	r.GET("/api/login/facebook", authenticationHandler.Facebook)
 
 	r.POST("/api/locations/import/pkorg", locationHandler.ImportPkOrgSpot)
+	r.POST("/api/trainings/import/ical", trainingHandler.ImportCalendar)
+	r.POST("/api/trainings/validate", trainingHandler.ValidateTraining)
 
+	r.GET("/api/calendar", trainingHandler.GetCalendar)
+	r.GET("/api/event", trainingHandler.GetEvents)
+	r.GET("/api/event/:key", withCalendar(trainingHandler.GetEventCalendar, trainingHandler.GetEvent))
+	r.GET("/api/holidays/:state", trainingHandler.GetHolidays)
+	r.GET("/api/reports/attendance", trainingHandler.GetAttendanceReport)
 	r.GET("/api/training", queryHandler.GetTrainings)
-	r.GET("/api/training/:key", queryHandler.GetTraining)
+	r.GET("/api/training.ics", trainingHandler.GetTrainingsCalendar)
+	r.GET("/api/training/:key", withCalendar(trainingHandler.GetTrainingCalendar, queryHandler.GetTraining))
+	r.GET("/api/training/:key/occurrences", trainingHandler.GetOccurrences)
+	r.POST("/api/training/:key/occurrences", trainingHandler.AddOccurrence)
+	r.POST("/api/training/:key/occurrences/:date/cancel", trainingHandler.CancelOccurrence)
+	r.POST("/api/training/:key/occurrences/:date/move", trainingHandler.MoveOccurrence)
+	r.POST("/api/training/:key/occurrences/:date/rsvp", trainingHandler.Register)
+	r.DELETE("/api/training/:key/occurrences/:date/rsvp", trainingHandler.Unregister)
+	r.GET("/api/training/:key/occurrences/:date/attendees", trainingHandler.GetAttendees)
+	r.GET("/api/training/:key/occurrences/:date/attendance", trainingHandler.GetAttendance)
+	r.PUT("/api/training/:key/occurrences/:date/attendance", trainingHandler.PutAttendance)
+	r.PUT("/api/training/:key/occurrences/:date/coaches", trainingHandler.PutCoaches)
+	r.GET("/api/autocomplete", searchHandler.GetSuggestions)
+	r.GET("/api/search", searchHandler.GetSearch)
 	r.GET("/api/page", queryHandler.GetPages)
 	r.GET("/api/page/:key", queryHandler.GetPage)
 	r.GET("/api/location", queryHandler.GetLocations)
@@ -129,10 +184,24 @@ func NewServer(configPath string, test bool) *http.Server {
 	r.GET("/api/user/:key/email", userHandler.RequestEmail)
 	r.GET("/api/user/:key/email/:login", userHandler.EnableEmail)
 	r.POST("/api/user/:key/photos", userPhotoHandler.UpdatePhotos)
+	r.GET("/api/user/:key/trainings.ics", trainingHandler.GetOrganiserCalendar)
+	r.GET("/api/user/:key/duties", trainingHandler.GetDuties)
 
-	r.POST("/api/user/:key/comment", userHandler.AddComment)
-	r.PUT("/api/user/:key/comment", userHandler.EditComment)
-	r.DELETE("/api/user/:key/comment", userHandler.DeleteComment)
+	r.POST("/api/user/:key/comment", userCommentHandler.AddComment)
+	r.PUT("/api/user/:key/comment", userCommentHandler.EditComment)
+	r.DELETE("/api/user/:key/comment", userCommentHandler.DeleteComment)
+	r.POST("/api/training/:key/comment", trainingCommentHandler.AddComment)
+	r.PUT("/api/training/:key/comment", trainingCommentHandler.EditComment)
+	r.DELETE("/api/training/:key/comment", trainingCommentHandler.DeleteComment)
+	r.POST("/api/location/:key/comment", locationCommentHandler.AddComment)
+	r.PUT("/api/location/:key/comment", locationCommentHandler.EditComment)
+	r.DELETE("/api/location/:key/comment", locationCommentHandler.DeleteComment)
+	r.POST("/api/page/:key/comment", pageCommentHandler.AddComment)
+	r.PUT("/api/page/:key/comment", pageCommentHandler.EditComment)
+	r.DELETE("/api/page/:key/comment", pageCommentHandler.DeleteComment)
+	r.POST("/api/event/:key/comment", eventCommentHandler.AddComment)
+	r.PUT("/api/event/:key/comment", eventCommentHandler.EditComment)
+	r.DELETE("/api/event/:key/comment", eventCommentHandler.DeleteComment)
 
 	r.POST("/api/server/mail", serverHandler.ChangeMailPassword)
 	r.POST("/api/server/minecraft/whitelist", serverHandler.AddUsernameToWhitelist)
@@ -177,3 +246,14 @@ func Version(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params
 	// the only endpoint that does not use JSON-formatted response, i.e. no quotes around version string
 	api.Success(w, r, []byte(dpv.ConfigInstance.Settings.Version))
 }
+
+// withCalendar serves the iCalendar feed if the key ends with .ics, as httprouter cannot match a suffix of a parameter
+func withCalendar(calendar httprouter.Handle, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, urlParams httprouter.Params) {
+		if strings.HasSuffix(urlParams.ByName("key"), training.CalendarSuffix) {
+			calendar(w, r, urlParams)
+			return
+		}
+		handle(w, r, urlParams)
+	}
+}
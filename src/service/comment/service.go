@@ -0,0 +1,124 @@
+package comment
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/graph"
+	"pkv/api/src/repository/t"
+	"pkv/api/src/service/description"
+	"time"
+)
+
+// Service manages the comments of any entity that can be commented, such as users, trainings, locations and pages
+type Service[T graph.CommentEntity] struct {
+	db *graph.Db
+	em graph.EntityManager[T]
+}
+
+func NewService[T graph.CommentEntity](db *graph.Db, em graph.EntityManager[T]) *Service[T] {
+	return &Service[T]{db: db, em: em}
+}
+
+func (s *Service[T]) Add(key string, author string, title string, text string, ctx context.Context) error {
+	entity, err := s.em.Read(key, ctx)
+	if err != nil {
+		return t.Errorf("reading entity failed: %w", err)
+	}
+	comment, err := s.render(title, text, ctx)
+	if err != nil {
+		return err
+	}
+	comment.Author = author
+	comment.Created = time.Now()
+	for _, c := range entity.GetComments() {
+		if c.Title == comment.Title {
+			return t.Errorf("comment with same title already exists")
+		}
+	}
+	entity.SetComments(append(entity.GetComments(), comment))
+	if err = s.em.Update(entity, ctx); err != nil {
+		return t.Errorf("updating entity failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Service[T]) Edit(key string, author string, oldTitle string, title string, text string, ctx context.Context) error {
+	entity, err := s.em.Read(key, ctx)
+	if err != nil {
+		return t.Errorf("reading entity failed: %w", err)
+	}
+	edited, err := s.render(title, text, ctx)
+	if err != nil {
+		return err
+	}
+	comments := entity.GetComments()
+	var comment *domain.Comment
+	for n, c := range comments {
+		if c.Title == oldTitle {
+			comment = &comments[n]
+		} else if c.Title == edited.Title {
+			return t.Errorf("comment with same title already exists")
+		}
+	}
+	if comment == nil {
+		return t.Errorf("comment not found")
+	}
+	if comment.Author != author {
+		return t.Errorf("not authorized to edit comment")
+	}
+	comment.Title = edited.Title
+	comment.Text = edited.Text
+	comment.Render = edited.Render
+	entity.SetComments(comments)
+	if err = s.em.Update(entity, ctx); err != nil {
+		return t.Errorf("updating entity failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Service[T]) Delete(key string, author string, title string, ctx context.Context) error {
+	entity, err := s.em.Read(key, ctx)
+	if err != nil {
+		return t.Errorf("reading entity failed: %w", err)
+	}
+	var newComments []domain.Comment
+	for _, c := range entity.GetComments() {
+		if c.Title != title {
+			newComments = append(newComments, c)
+		} else if c.Author != author {
+			return t.Errorf("not authorized to delete comment")
+		}
+	}
+	if len(newComments) == len(entity.GetComments()) {
+		return t.Errorf("comment not found")
+	}
+	entity.SetComments(newComments)
+	if err = s.em.Update(entity, ctx); err != nil {
+		return t.Errorf("updating entity failed: %w", err)
+	}
+	return nil
+}
+
+// render normalises the title into the first line of the text, renders the text with its mentions and references and
+// checks the lengths of both
+func (s *Service[T]) render(title string, text string, ctx context.Context) (domain.Comment, error) {
+	text = description.FixTitle(title, text)
+	title = description.GetTitle(text)
+	if title == "" {
+		return domain.Comment{}, t.Errorf("title cannot be empty")
+	}
+	if len(title) > 100 {
+		return domain.Comment{}, t.Errorf("title cannot be longer than 100 characters")
+	}
+	if text == "" {
+		return domain.Comment{}, t.Errorf("text cannot be empty")
+	}
+	if len(text) > 10000 {
+		return domain.Comment{}, t.Errorf("text cannot be longer than 10000 characters")
+	}
+	return domain.Comment{
+		Title:  title,
+		Text:   text,
+		Render: description.RenderWith([]byte(text), s.db, "", ctx),
+	}, nil
+}
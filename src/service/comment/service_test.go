@@ -1,4 +1,4 @@
-package user
+package comment
 
 import (
 	"context"
@@ -18,32 +18,32 @@ func TestComments(t *testing.T) {
 	if err != nil {
 		t.Fatalf("initialisation failed: %s", err)
 	}
-	service := NewService(db)
-	err = service.AddComment(user.Key, "author", "title", "text", context.Background())
+	service := NewService(db, db.Users)
+	err = service.Add(user.Key, "author", "title", "text", context.Background())
 	if err != nil {
 		t.Fatalf("add comment failed: %s", err)
 	}
-	err = service.AddComment(user.Key, "author", "title2", "text2", context.Background())
+	err = service.Add(user.Key, "author", "title2", "text2", context.Background())
 	if err != nil {
 		t.Fatalf("add comment failed: %s", err)
 	}
-	err = service.AddComment(user.Key, "author", "title2", "text2", context.Background())
+	err = service.Add(user.Key, "author", "title2", "text2", context.Background())
 	if err == nil {
 		t.Fatalf("add comment should fail")
 	}
-	err = service.EditComment(user.Key, "author", "title2", "title3", "text3", context.Background())
+	err = service.Edit(user.Key, "author", "title2", "title3", "text3", context.Background())
 	if err != nil {
 		t.Fatalf("edit comment failed: %s", err)
 	}
-	err = service.EditComment(user.Key, "author", "title2", "title4", "text4", context.Background())
+	err = service.Edit(user.Key, "author", "title2", "title4", "text4", context.Background())
 	if err == nil {
 		t.Fatalf("edit comment should fail")
 	}
-	err = service.EditComment(user.Key, "author", "title3", "title3", "text3", context.Background())
+	err = service.Edit(user.Key, "author", "title3", "title3", "text3", context.Background())
 	if err != nil {
 		t.Fatalf("edit comment failed: %s", err)
 	}
-	err = service.EditComment(user.Key, "wrong_user", "title3", "title3", "text3", context.Background())
+	err = service.Edit(user.Key, "wrong_user", "title3", "title3", "text3", context.Background())
 	if err == nil {
 		t.Fatalf("edit comment should fail")
 	}
@@ -66,11 +66,11 @@ func TestComments(t *testing.T) {
 	if puser.Comments[1].Author != "author" {
 		t.Fatalf("wrong comment author: %s", puser.Comments[0].Author)
 	}
-	err = service.DeleteComment(user.Key, "wrong_user", "title3", context.Background())
+	err = service.Delete(user.Key, "wrong_user", "title3", context.Background())
 	if err == nil {
 		t.Fatalf("delete comment should fail")
 	}
-	err = service.DeleteComment(user.Key, "author", "title3", context.Background())
+	err = service.Delete(user.Key, "author", "title3", context.Background())
 	if err != nil {
 		t.Fatalf("delete comment failed: %s", err)
 	}
@@ -91,3 +91,31 @@ func TestComments(t *testing.T) {
 		t.Fatalf("wrong comment text: %s", puser.Comments[0].Text)
 	}
 }
+
+func TestTrainingComments(t *testing.T) {
+	db, _, err := graph.Init("../../../config.yml", true)
+	if err != nil {
+		t.Fatalf("db initialisation failed: %s", err)
+	}
+	training := domain.Training{}
+	err = db.Trainings.Create(&training, context.Background())
+	if err != nil {
+		t.Fatalf("initialisation failed: %s", err)
+	}
+	service := NewService(db, db.Trainings)
+	err = service.Add(training.Key, "author", "title", "text", context.Background())
+	if err != nil {
+		t.Fatalf("add comment failed: %s", err)
+	}
+	err = service.Delete(training.Key, "wrong_user", "title", context.Background())
+	if err == nil {
+		t.Fatalf("delete comment should fail")
+	}
+	ptraining, err := db.Trainings.Read(training.Key, context.Background())
+	if err != nil {
+		t.Fatalf("read training failed: %s", err)
+	}
+	if len(ptraining.Comments) != 1 || ptraining.Comments[0].Author != "author" {
+		t.Fatalf("wrong comments: %+v", ptraining.Comments)
+	}
+}
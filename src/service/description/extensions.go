@@ -0,0 +1,146 @@
+package description
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"pkv/api/src/repository/t"
+	"regexp"
+	"strings"
+)
+
+// Resolver returns the label of an entity referenced in a description and whether the entity exists
+type Resolver interface {
+	Resolve(kind, key, language string, ctx context.Context) (string, bool)
+}
+
+// embed is a video platform whose clips can be embedded
+type embed struct {
+	name   string
+	id     *regexp.Regexp
+	watch  string
+	player string
+}
+
+var (
+	// mention matches @username, the characters around it are checked by mentions
+	mention = regexp.MustCompile(`@([a-z0-9_-][a-z0-9_.-]{1,28}[a-z0-9_-])`)
+	// reference matches [[kind:key]] and [[kind:key|label]]
+	reference = regexp.MustCompile(`\[\[([a-z]+):([^\]|\s]+)(?:\|([^\]]*))?]]`)
+	// codeSpan matches inline code, which is never extended
+	codeSpan = regexp.MustCompile("`[^`]*`")
+	// embeds allow-lists the video platforms by the kind used in references
+	embeds = map[string]embed{
+		"youtube": {"YouTube", regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`), "https://www.youtube.com/watch?v=%s", "https://www.youtube-nocookie.com/embed/%s"},
+		"vimeo":   {"Vimeo", regexp.MustCompile(`^\d{1,12}$`), "https://vimeo.com/%s", "https://player.vimeo.com/video/%s?dnt=1"},
+	}
+	// player matches the players of the allow-listed video platforms, which are the only embeds passing sanitisation
+	player = regexp.MustCompile(`^https://(www\.youtube-nocookie\.com/embed/[A-Za-z0-9_-]{11}|player\.vimeo\.com/video/\d{1,12}\?dnt=1)$`)
+)
+
+// Extend replaces the extensions of Markdown used in descriptions outside of code by plain Markdown and HTML:
+// @username mentions and [[kind:key]] references to users, locations, trainings, events and pages become links to
+// the entities, labelled by the resolver unless a label is given as in [[location:123|label]]. Mentions and
+// references that cannot be resolved are kept as written. [[youtube:id]] and [[vimeo:id]] become placeholders that
+// link to the video, whose player is only loaded by the frontend after they have been clicked.
+func Extend(md string, resolver Resolver, language string, ctx context.Context) string {
+	lines := strings.Split(md, "\n")
+	code := false
+	for i, line := range lines {
+		if fence.MatchString(line) {
+			code = !code
+			continue
+		}
+		if code {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		for _, span := range codeSpan.FindAllStringIndex(line, -1) {
+			b.WriteString(references(line[last:span[0]], resolver, language, ctx))
+			b.WriteString(line[span[0]:span[1]])
+			last = span[1]
+		}
+		b.WriteString(references(line[last:], resolver, language, ctx))
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func references(text string, resolver Resolver, language string, ctx context.Context) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range reference.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(mentions(text[last:match[0]], resolver, language, ctx))
+		last = match[1]
+		kind, key, label := text[match[2]:match[3]], text[match[4]:match[5]], ""
+		if match[6] >= 0 {
+			label = strings.TrimSpace(text[match[6]:match[7]])
+		}
+		if e, ok := embeds[kind]; ok && e.id.MatchString(key) {
+			if label == "" {
+				label = fmt.Sprintf(t.T(language, "Load video from %s"), e.name)
+			}
+			b.WriteString(fmt.Sprintf(`<a class="embed" href="%s" data-embed="%s">%s</a>`,
+				fmt.Sprintf(e.watch, key), html.EscapeString(fmt.Sprintf(e.player, key)), html.EscapeString(label)))
+			continue
+		}
+		b.WriteString(link(text[match[0]:match[1]], kind, key, label, resolver, language, ctx))
+	}
+	b.WriteString(mentions(text[last:], resolver, language, ctx))
+	return b.String()
+}
+
+// mentions links @username unless it is part of a word, an e-mail address or a path
+func mentions(text string, resolver Resolver, language string, ctx context.Context) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range mention.FindAllStringSubmatchIndex(text, -1) {
+		if match[0] > 0 && (isWord(text[match[0]-1]) || strings.IndexByte("@./\\_-", text[match[0]-1]) >= 0) {
+			continue
+		}
+		if match[1] < len(text) && (isWord(text[match[1]]) || strings.IndexByte("@_-", text[match[1]]) >= 0) {
+			continue
+		}
+		b.WriteString(text[last:match[0]])
+		b.WriteString(link(text[match[0]:match[1]], "user", text[match[2]:match[3]], "", resolver, language, ctx))
+		last = match[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// link returns a Markdown link to the entity, or the label or else the original text if the entity does not exist
+func link(original, kind, key, label string, resolver Resolver, language string, ctx context.Context) string {
+	if resolver == nil {
+		return original
+	}
+	resolved, ok := resolver.Resolve(kind, key, language, ctx)
+	if !ok {
+		if label != "" {
+			return escape(label)
+		}
+		return original
+	}
+	if label == "" {
+		label = resolved
+	}
+	return "[" + escape(label) + "](/" + kind + "/" + url.PathEscape(key) + ")"
+}
+
+// escape escapes the characters which would be taken as Markdown or HTML in a link label
+func escape(label string) string {
+	var b strings.Builder
+	for _, c := range label {
+		if strings.ContainsRune("\\`*_[]<>!&", c) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func isWord(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
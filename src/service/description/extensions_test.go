@@ -0,0 +1,58 @@
+package description
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type resolver map[string]string
+
+func (r resolver) Resolve(kind, key, language string, ctx context.Context) (string, bool) {
+	label, ok := r[kind+":"+key]
+	return label, ok
+}
+
+func TestExtend(t *testing.T) {
+	entities := resolver{"user:max_m": "Max *M*", "location:123": "Park"}
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{"mention", "Hi @max_m.", "Hi [Max \\*M\\*](/user/max_m)."},
+		{"unknown mention", "Hi @nobody", "Hi @nobody"},
+		{"e-mail address", "max@max_m.de", "max@max_m.de"},
+		{"reference", "At [[location:123]]!", "At [Park](/location/123)!"},
+		{"labelled reference", "[[location:123|the park]]", "[the park](/location/123)"},
+		{"unknown reference", "[[training:1]] [[training:2|Jam]]", "[[training:1]] Jam"},
+		{"unknown kind", "[[script:1]]", "[[script:1]]"},
+		{"code", "`@max_m` and\n```\n[[location:123]]\n```", "`@max_m` and\n```\n[[location:123]]\n```"},
+		{"youtube", "[[youtube:dQw4w9WgXcQ]]", `<a class="embed" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ" data-embed="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ">Load video from YouTube</a>`},
+		{"vimeo", "[[vimeo:76979871|<Clip>]]", `<a class="embed" href="https://vimeo.com/76979871" data-embed="https://player.vimeo.com/video/76979871?dnt=1">&lt;Clip&gt;</a>`},
+		{"invalid video", "[[youtube:x\"onclick]]", "[[youtube:x\"onclick]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extend(tt.md, entities, "en", context.Background()); got != tt.want {
+				t.Errorf("Extend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderWith(t *testing.T) {
+	md := "# Clip\n\n[[vimeo:76979871]] by @max_m\n\n<a class=\"embed\" data-embed=\"https://evil.example/\" onclick=\"alert(1)\">x</a>"
+	got := RenderWith([]byte(md), resolver{"user:max_m": "Max"}, "en", context.Background())
+	for _, want := range []string{
+		`<a class="embed" href="https://vimeo.com/76979871" data-embed="https://player.vimeo.com/video/76979871?dnt=1" rel="nofollow noopener" target="_blank">Load video from Vimeo</a>`,
+		`<a href="/user/max_m" rel="nofollow">Max</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderWith() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "evil") || strings.Contains(got, "onclick") {
+		t.Errorf("RenderWith() = %q, want embeds of other sites removed", got)
+	}
+}
@@ -0,0 +1,131 @@
+package description
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"pkv/api/src/repository/t"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Glossary lists parkour terms that are never translated
+var Glossary = []string{
+	"Parkour", "Freerunning", "Traceur", "Traceuse", "Jam", "Precision", "Kong", "Double Kong", "Cat Leap",
+	"Lache", "Wallrun", "Tic Tac", "Speed Vault", "Dash Vault", "Lazy Vault", "Safety Vault", "Underbar", "Palm Spin",
+	"Climb Up",
+}
+
+var (
+	// fence starts or ends a fenced code block
+	fence = regexp.MustCompile("^\\s*(```|~~~)")
+	// blockPrefix matches the indentation, headings, quotes and list markers at the beginning of a line
+	blockPrefix = regexp.MustCompile(`^\s*(?:(?:#{1,6}|>|[-*+]|\d+[.)])\s+)*`)
+	// markup matches code spans, references, mentions, link targets, URLs, HTML tags, table separators and characters
+	// XML reserves
+	markup = regexp.MustCompile("`[^`]*`|\\[\\[[^\\]]*]]|@[a-z0-9_-][a-z0-9_.-]*|\\]\\([^)]*\\)|<https?://[^>]*>|https?://[^\\s)]+|<[^>]+>|\\||[<>&]")
+	// placeholder matches the placeholders of kept parts, including variants translators produce
+	placeholder = regexp.MustCompile(`(?i)<x\s*id\s*=\s*"(\d+)"\s*/?>(?:</x>)?`)
+)
+
+// TranslateMarkdown translates a Markdown document line by line, keeping blank lines, code blocks, block prefixes,
+// code spans, link targets, URLs, HTML and the terms of the glossary unchanged. The kept parts are replaced by
+// placeholders while translating, and a translation that loses any of them is rejected.
+func TranslateMarkdown(translator Translator, glossary []string, text, source, target string, ctx context.Context) (string, error) {
+	terms := glossaryPattern(append(slices.Clone(Glossary), glossary...))
+	lines := strings.Split(text, "\n")
+	var indices []int
+	var texts []string
+	var kept [][]string
+	code := false
+	for i, line := range lines {
+		if fence.MatchString(line) {
+			code = !code
+			continue
+		}
+		prefix := blockPrefix.FindString(line)
+		if code || !strings.ContainsFunc(line[len(prefix):], unicode.IsLetter) {
+			continue
+		}
+		masked, parts := mask(line[len(prefix):], terms)
+		indices = append(indices, i)
+		texts = append(texts, masked)
+		kept = append(kept, parts)
+	}
+	if len(texts) == 0 {
+		return text, nil
+	}
+	translated, err := translator.Translate(texts, source, target, ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(translated) != len(texts) {
+		return "", t.Errorf("received %d translations for %d lines", len(translated), len(texts))
+	}
+	for n, i := range indices {
+		line, err := unmask(translated[n], kept[n])
+		if err != nil {
+			return "", err
+		}
+		lines[i] = blockPrefix.FindString(lines[i]) + line
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// glossaryPattern matches any of the terms as whole words, preferring longer terms
+func glossaryPattern(terms []string) *regexp.Regexp {
+	slices.SortFunc(terms, func(a, b string) int { return len(b) - len(a) })
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// mask replaces markup and terms by numbered placeholders and returns the replaced parts
+func mask(line string, terms *regexp.Regexp) (string, []string) {
+	var parts []string
+	replace := func(part string) string {
+		parts = append(parts, part)
+		return fmt.Sprintf(`<x id="%d"/>`, len(parts)-1)
+	}
+	line = markup.ReplaceAllStringFunc(line, replace)
+	var b strings.Builder
+	last := 0
+	for _, match := range terms.FindAllStringIndex(line, -1) {
+		b.WriteString(line[last:match[0]])
+		b.WriteString(replace(line[match[0]:match[1]]))
+		last = match[1]
+	}
+	b.WriteString(line[last:])
+	return b.String(), parts
+}
+
+// unmask restores the parts replaced by mask, and fails if the translation lost or invented any placeholder. Entities
+// escaped by the translator are unescaped, as the characters XML reserves have been replaced.
+func unmask(line string, parts []string) (string, error) {
+	line = html.UnescapeString(line)
+	found := make([]bool, len(parts))
+	var err error
+	line = placeholder.ReplaceAllStringFunc(line, func(match string) string {
+		n, _ := strconv.Atoi(placeholder.FindStringSubmatch(match)[1])
+		if n >= len(parts) {
+			err = t.Errorf("translation contains unknown markup %s", match)
+			return match
+		}
+		found[n] = true
+		return parts[n]
+	})
+	if err != nil {
+		return "", err
+	}
+	if missing := slices.Index(found, false); missing >= 0 {
+		return "", t.Errorf("translation lost markup %s", parts[missing])
+	}
+	return line, nil
+}
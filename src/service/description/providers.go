@@ -0,0 +1,150 @@
+package description
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"pkv/api/src/repository/t"
+	"strings"
+)
+
+// DeepL translates via the DeepL API, see https://developers.deepl.com/docs/api-reference/translate
+type DeepL struct {
+	Url string
+	Key string
+}
+
+func (d DeepL) Translate(texts []string, source, target string, ctx context.Context) ([]string, error) {
+	form := url.Values{
+		"text":                texts,
+		"source_lang":         {strings.ToUpper(source)},
+		"target_lang":         {deepLTarget(target)},
+		"tag_handling":        {"xml"},
+		"preserve_formatting": {"1"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, t.Errorf("creating translation request failed: %w", err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+d.Key)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "dpv-api")
+	var response struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := do(req, &response); err != nil {
+		return nil, err
+	}
+	translated := make([]string, 0, len(response.Translations))
+	for _, translation := range response.Translations {
+		translated = append(translated, translation.Text)
+	}
+	return translated, nil
+}
+
+// deepLTarget returns the target language code DeepL expects, which requires a variant for English and Portuguese
+func deepLTarget(language string) string {
+	switch language {
+	case "en":
+		return "EN-GB"
+	case "pt":
+		return "PT-PT"
+	case "no":
+		return "NB"
+	}
+	return strings.ToUpper(language)
+}
+
+// LibreTranslate translates via a self-hosted server compatible with https://libretranslate.com
+type LibreTranslate struct {
+	Url string
+	Key string
+}
+
+func (l LibreTranslate) Translate(texts []string, source, target string, ctx context.Context) ([]string, error) {
+	request := map[string]any{
+		"q":      texts,
+		"source": libreLanguage(source),
+		"target": libreLanguage(target),
+		"format": "html",
+	}
+	if l.Key != "" {
+		request["api_key"] = l.Key
+	}
+	var response struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := postJson(strings.TrimSuffix(l.Url, "/")+"/translate", request, &response, ctx); err != nil {
+		return nil, err
+	}
+	return response.TranslatedText, nil
+}
+
+// libreLanguage returns the language code LibreTranslate expects, which names Norwegian Bokmål explicitly
+func libreLanguage(language string) string {
+	if language == "no" {
+		return "nb"
+	}
+	return language
+}
+
+// Gemini translates via the proxy at /api/openai/v1/chat/completions, which forwards OpenAI-style chat completions to
+// Gemini and only accepts requests from the server itself
+type Gemini struct {
+	Url string
+}
+
+func (g Gemini) Translate(texts []string, source, target string, ctx context.Context) ([]string, error) {
+	content, err := json.Marshal(texts)
+	if err != nil {
+		return nil, t.Errorf("encoding translation request failed: %w", err)
+	}
+	instruction := fmt.Sprintf("You translate parkour related texts from the language with the ISO 639-1 code %s into the language with the code %s. "+
+		"You receive a JSON array of strings and answer with a JSON array of their translations in the same order, without any other text. "+
+		"Keep XML tags such as <x id=\"0\"/> exactly as they are.", source, target)
+	request := map[string]any{
+		"model": "gemini",
+		"messages": []map[string]string{
+			{"role": "system", "content": instruction},
+			{"role": "user", "content": string(content)},
+		},
+		"max_tokens":  8192,
+		"temperature": 0.2,
+	}
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJson(g.Url, request, &response, ctx); err != nil {
+		return nil, err
+	}
+	if len(response.Choices) == 0 {
+		return nil, t.Errorf("no content in Gemini response")
+	}
+	answer := strings.TrimSpace(response.Choices[0].Message.Content)
+	answer = strings.TrimPrefix(strings.TrimPrefix(answer, "```json"), "```")
+	answer = strings.TrimSuffix(strings.TrimSpace(answer), "```")
+	var translated []string
+	if err := json.Unmarshal([]byte(answer), &translated); err != nil {
+		return nil, t.Errorf("decoding translation response failed: %w, response: %v", err, answer)
+	}
+	return translated, nil
+}
+
+// Fake prefixes every text with the target language in brackets, for testing without a translation service
+type Fake struct{}
+
+func (Fake) Translate(texts []string, source, target string, ctx context.Context) ([]string, error) {
+	translated := make([]string, 0, len(texts))
+	for _, text := range texts {
+		translated = append(translated, "["+target+"] "+text)
+	}
+	return translated, nil
+}
@@ -1,37 +1,49 @@
 package description
 
 import (
+	"context"
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 	"github.com/microcosm-cc/bluemonday"
+	"pkv/api/src/domain"
+	"regexp"
 	"strings"
 )
 
-var p *parser.Parser
-var r *html.Renderer
+// policy sanitises rendered HTML. It is built once, as it may be used concurrently but not changed, whereas parsers
+// and renderers keep state while rendering and are created for every rendering.
+var policy = newPolicy()
 
-var b *bluemonday.Policy
+func newPolicy() *bluemonday.Policy {
+	b := bluemonday.UGCPolicy().AddTargetBlankToFullyQualifiedLinks(true)
+	b.AllowAttrs("class").Matching(regexp.MustCompile(`^embed$`)).OnElements("a")
+	b.AllowAttrs("data-embed").Matching(player).OnElements("a")
+	return b
+}
 
 // FixTitle takes a title and text as input, normalises the text's first line into a h1 heading and adds
-// block-separating newlines if necessary. If the text doesn't start with a heading, a provided heading is added.
+// block-separating newlines if necessary. A provided title is kept: it is added as heading unless the text starts with
+// a heading of the same title, which keeps imported titles and makes FixTitle idempotent together with GetTitle.
 func FixTitle(title string, text string) string {
+	title = strings.TrimSpace(title)
 	lines := strings.Split(text, "\n")
 	if len(lines) > 0 {
 		firstLine := strings.TrimSpace(lines[0])
 		if !strings.HasPrefix(firstLine, "#") {
-			return "# " + strings.TrimSpace(title) + "\n\n" + text
-		} else {
-			for strings.HasPrefix(firstLine, "#") {
-				firstLine = strings.TrimPrefix(firstLine, "#")
-			}
-			firstLine = "# " + strings.TrimSpace(firstLine)
-			lines[0] = firstLine
+			return "# " + title + "\n\n" + text
+		}
+		for strings.HasPrefix(firstLine, "#") {
+			firstLine = strings.TrimPrefix(firstLine, "#")
 		}
+		if heading := strings.TrimSpace(firstLine); title != "" && heading != title {
+			return "# " + title + "\n\n" + text
+		}
+		lines[0] = "# " + strings.TrimSpace(firstLine)
 	}
 	if len(lines) > 1 && len(lines[1]) > 0 {
 		lines[1] = "\n" + lines[1]
-	} else {
+	} else if len(lines) == 1 {
 		lines[0] += "\n\n"
 	}
 	return strings.Join(lines, "\n")
@@ -49,26 +61,46 @@ func GetTitle(text string) string {
 	return ""
 }
 
+// Render renders Markdown to sanitised HTML, without resolving mentions and references, see RenderWith
 func Render(md []byte) string {
-	// if p == nil {
-	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
-	p = parser.NewWithExtensions(extensions)
-	// }
+	return RenderWith(md, nil, "", context.Background())
+}
 
+// RenderWith renders Markdown with the extensions of Extend in the given language to sanitised HTML
+func RenderWith(md []byte, resolver Resolver, language string, ctx context.Context) string {
+	md = []byte(Extend(string(md), resolver, language, ctx))
+	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
 	// parser can not be recycled because p.tip is nil on second execution.
-	doc := p.Parse(md)
+	doc := parser.NewWithExtensions(extensions).Parse(md)
 
-	if r == nil {
-		htmlFlags := html.CommonFlags | html.HrefTargetBlank | html.NofollowLinks | html.NoreferrerLinks | html.NoopenerLinks | html.LazyLoadImages
-		opts := html.RendererOptions{Flags: htmlFlags}
-		r = html.NewRenderer(opts)
-	}
+	htmlFlags := html.CommonFlags | html.HrefTargetBlank | html.NofollowLinks | html.NoreferrerLinks | html.NoopenerLinks | html.LazyLoadImages
+	r := html.NewRenderer(html.RendererOptions{Flags: htmlFlags})
+	rendered := markdown.Render(doc, r)
 
-	if b == nil {
-		b = bluemonday.UGCPolicy().AddTargetBlankToFullyQualifiedLinks(true)
-	}
+	return policy.Sanitize(string(rendered))
+}
 
-	html := markdown.Render(doc, r)
+// RenderAll normalises the title of every description into the first line of its text and renders the text to
+// sanitised HTML, replacing any rendering sent by a client. Descriptions without title and text are emptied including
+// their translated flag, so that they are translated again. Mentions and references are resolved by the resolver,
+// which may be nil.
+func RenderAll(descriptions domain.Descriptions, resolver Resolver, ctx context.Context) {
+	for language, d := range descriptions {
+		if strings.TrimSpace(d.Title) == "" && strings.TrimSpace(d.Text) == "" {
+			descriptions[language] = domain.Description{}
+			continue
+		}
+		d.Text = FixTitle(d.Title, d.Text)
+		d.Title = GetTitle(d.Text)
+		d.Render = RenderWith([]byte(d.Text), resolver, language, ctx)
+		descriptions[language] = d
+	}
+}
 
-	return b.Sanitize(string(html))
+// RenderHook calls RenderAll before entities are written through the generic CRUD endpoints
+func RenderHook[T domain.Described](resolver Resolver) func(item T, ctx context.Context) error {
+	return func(item T, ctx context.Context) error {
+		RenderAll(item.GetDescriptions(), resolver, ctx)
+		return nil
+	}
 }
@@ -0,0 +1,70 @@
+package description
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFixTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		text  string
+		want  string
+	}{
+		{"heading added", "Title", "text", "# Title\n\ntext"},
+		{"heading normalised", "", "### Heading\ntext", "# Heading\n\ntext"},
+		{"heading of the title normalised", "Title", "### Title\ntext", "# Title\n\ntext"},
+		{"title kept above another heading", "Title", "### Heading\ntext", "# Title\n\n### Heading\ntext"},
+		{"heading only", "", "# Heading", "# Heading\n\n"},
+		{"already fixed", "", "# Heading\n\ntext", "# Heading\n\ntext"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FixTitle(tt.title, tt.text)
+			if got != tt.want {
+				t.Errorf("FixTitle() = %q, want %q", got, tt.want)
+			}
+			if again := FixTitle(GetTitle(got), got); again != got {
+				t.Errorf("FixTitle() is not idempotent, got %q after %q", again, got)
+			}
+		})
+	}
+}
+
+func TestRenderAll(t *testing.T) {
+	descriptions := domain.Descriptions{
+		"de": {Title: " Training ", Text: "Mit <script>alert(1)</script>", Render: "<script>alert(1)</script>"},
+		"en": {Render: "<img src=x onerror=alert(1)>", Translated: true},
+	}
+	RenderAll(descriptions, nil, context.Background())
+	de := descriptions["de"]
+	if de.Title != "Training" || de.Text != "# Training\n\nMit <script>alert(1)</script>" {
+		t.Errorf("RenderAll() title = %q, text = %q", de.Title, de.Text)
+	}
+	if strings.Contains(de.Render, "script") || !strings.Contains(de.Render, "<h1 id=\"training\">Training</h1>") {
+		t.Errorf("RenderAll() render = %q, want the sanitised text", de.Render)
+	}
+	if en := descriptions["en"]; en != (domain.Description{}) {
+		t.Errorf("RenderAll() = %+v, want an empty description", en)
+	}
+}
+
+func TestRenderConcurrently(t *testing.T) {
+	md := []byte("# Heading\n\nSome *text* with [a link](https://example.com)")
+	want := Render(md)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := Render(md); got != want {
+				t.Errorf("Render() = %q, want %q", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
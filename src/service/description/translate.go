@@ -1,62 +1,66 @@
 package description
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"net/http"
-	"net/url"
 	"pkv/api/src/repository/dpv"
 	"pkv/api/src/repository/t"
 )
 
-// TranslateDocument translates a document from one language to another.
-func TranslateDocument(text, srcLang, destLang string, ctx context.Context) (string, error) {
-	deeplKey := dpv.ConfigInstance.Auth.DeepLKey
-	deeplUrl, err := url.Parse(dpv.ConfigInstance.Auth.DeepLUrl)
-	if err != nil {
-		return "", t.Errorf("invalid DeepL url: %w", err)
+// Translator translates texts between languages given as ISO 639-1 codes. The texts may contain self-closing XML tags,
+// which need to be kept unchanged.
+type Translator interface {
+	Translate(texts []string, source, target string, ctx context.Context) ([]string, error)
+}
+
+// NewTranslator returns the translator configured as translation provider, DeepL by default
+func NewTranslator(config *dpv.Config) (Translator, error) {
+	switch config.Translation.Provider {
+	case "", "deepl":
+		return DeepL{Url: config.Auth.DeepLUrl, Key: config.Auth.DeepLKey}, nil
+	case "libretranslate":
+		return LibreTranslate{Url: config.Translation.Url, Key: config.Translation.Key}, nil
+	case "gemini":
+		return Gemini{Url: config.Translation.Url}, nil
+	case "fake":
+		return Fake{}, nil
 	}
-	q := deeplUrl.Query()
-	q.Add("auth_key", deeplKey)
-	q.Add("text", text)
-	q.Add("source_lang", srcLang)
-	q.Add("target_lang", destLang)
-	deeplUrl.RawQuery = q.Encode()
-	req, err := http.NewRequest(http.MethodPost, deeplUrl.String(), nil)
+	return nil, t.Errorf("unknown translation provider %s", config.Translation.Provider)
+}
+
+// postJson sends the request as JSON and decodes the JSON response, reporting the body of unsuccessful responses
+func postJson(url string, request any, response any, ctx context.Context) error {
+	body, err := json.Marshal(request)
 	if err != nil {
-		return "", t.Errorf("creating DeepL request failed: %w", err)
+		return t.Errorf("encoding translation request failed: %w", err)
 	}
-	req.Header.Add("User-Agent", "dpv-api")
-	if ctx != nil {
-		req = req.WithContext(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return t.Errorf("creating translation request failed: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "dpv-api")
+	return do(req, response)
+}
+
+func do(req *http.Request, response any) error {
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", t.Errorf("DeepL request failed: %w", err)
+		return t.Errorf("translation request failed: %w", err)
 	}
 	defer resp.Body.Close()
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		type ErrorResponse struct {
-			ErrMessage string `json:"message"`
-		}
-		outStruct := &ErrorResponse{}
-		if err := json.Unmarshal(bodyBytes, outStruct); err != nil {
-			return "", t.Errorf("DeepL request failed with status %v, decoding error JSON failed: %w, error message: %v", resp.StatusCode, err, string(bodyBytes))
-		}
-		return "", t.Errorf("DeepL request failed with status %v: %v", resp.StatusCode, outStruct.ErrMessage)
-	}
-	type translation struct {
-		DetectedSourceLanguage string `json:"detected_source_language"`
-		Text                   string `json:"text"`
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return t.Errorf("reading translation response failed: %w", err)
 	}
-	type TranslateResponse struct {
-		Translations []translation `json:"translations"`
+	if resp.StatusCode != http.StatusOK {
+		return t.Errorf("translation request failed with status %v: %v", resp.StatusCode, string(body))
 	}
-	outStruct := &TranslateResponse{}
-	if err := json.Unmarshal(bodyBytes, outStruct); err != nil {
-		return "", t.Errorf("DeepL request failed with status %v, decoding response JSON failed: %w, response: %v", resp.StatusCode, err, string(bodyBytes))
+	if err := json.Unmarshal(body, response); err != nil {
+		return t.Errorf("decoding translation response failed: %w, response: %v", err, string(body))
 	}
-	return outStruct.Translations[0].Text, nil
+	return nil
 }
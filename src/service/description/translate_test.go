@@ -1,11 +1,17 @@
 package description
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"pkv/api/src/repository/dpv"
+	"reflect"
+	"strings"
 	"testing"
 )
 
-func TestTranslateDocument(t *testing.T) {
+func TestDeepL_Translate(t *testing.T) {
 	t.Skip("Translation works, skip test to avoid hitting the DeepL API")
 	var err error
 	dpv.ConfigInstance, err = dpv.NewConfig("../../../config.yml")
@@ -34,15 +40,98 @@ func TestTranslateDocument(t *testing.T) {
 			"red",
 		},
 	}
+	translator := DeepL{Url: dpv.ConfigInstance.Auth.DeepLUrl, Key: dpv.ConfigInstance.Auth.DeepLKey}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := TranslateDocument(tt.text, tt.srcLang, tt.destLang, nil)
+			got, err := translator.Translate([]string{tt.text}, tt.srcLang, tt.destLang, context.Background())
 			if err != nil {
-				t.Errorf("TranslateDocument(%#v, %#v, %#v, %#v) error = %v", tt.text, tt.srcLang, tt.destLang, nil, err)
+				t.Errorf("Translate(%#v, %#v, %#v) error = %v", tt.text, tt.srcLang, tt.destLang, err)
 			}
-			if got != tt.want {
-				t.Errorf("TranslateDocument(%#v, %#v, %#v, %#v) got = %#v, want %#v", tt.text, tt.srcLang, tt.destLang, nil, got, tt.want)
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("Translate(%#v, %#v, %#v) got = %#v, want %#v", tt.text, tt.srcLang, tt.destLang, got, tt.want)
 			}
 		})
 	}
 }
+
+func TestLibreTranslate_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Q      []string `json:"q"`
+			Source string   `json:"source"`
+			Target string   `json:"target"`
+			Format string   `json:"format"`
+			Key    string   `json:"api_key"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || r.URL.Path != "/translate" {
+			http.Error(w, `{"error": "invalid request"}`, http.StatusBadRequest)
+			return
+		}
+		if request.Source != "de" || request.Target != "nb" || request.Format != "html" || request.Key != "secret" {
+			t.Errorf("LibreTranslate request = %+v", request)
+		}
+		translated := make([]string, len(request.Q))
+		for i, q := range request.Q {
+			translated[i] = strings.ToUpper(q)
+		}
+		json.NewEncoder(w).Encode(map[string][]string{"translatedText": translated})
+	}))
+	defer server.Close()
+	got, err := LibreTranslate{Url: server.URL + "/", Key: "secret"}.Translate([]string{"rot", "blau"}, "de", "no", context.Background())
+	if err != nil || !reflect.DeepEqual(got, []string{"ROT", "BLAU"}) {
+		t.Errorf("Translate() = %v, %v", got, err)
+	}
+	if _, err := (LibreTranslate{Url: server.URL + "/missing"}).Translate([]string{"rot"}, "de", "no", context.Background()); err == nil {
+		t.Errorf("Translate() error = nil for an unsuccessful response")
+	}
+}
+
+// translatorFunc turns a function into a Translator
+type translatorFunc func(text string) string
+
+func (f translatorFunc) Translate(texts []string, source, target string, ctx context.Context) ([]string, error) {
+	translated := make([]string, 0, len(texts))
+	for _, text := range texts {
+		translated = append(translated, f(text))
+	}
+	return translated, nil
+}
+
+func TestTranslateMarkdown(t *testing.T) {
+	text := "# Jam in Berlin\n\n" +
+		"Wir üben Precision und Kong auf dem [Spielplatz](https://example.com/spot) & mehr.\n" +
+		"- `code` bleibt | so\n" +
+		"\n" +
+		"```\n" +
+		"Code wird nicht übersetzt\n" +
+		"```\n" +
+		"---"
+	want := "# [en] Jam in Berlin\n\n" +
+		"[en] Wir üben Precision und Kong auf dem [Spielplatz](https://example.com/spot) & mehr.\n" +
+		"- [en] `code` bleibt | so\n" +
+		"\n" +
+		"```\n" +
+		"Code wird nicht übersetzt\n" +
+		"```\n" +
+		"---"
+	got, err := TranslateMarkdown(Fake{}, []string{"Berlin"}, text, "de", "en", context.Background())
+	if err != nil || got != want {
+		t.Errorf("TranslateMarkdown() = %q, %v, want %q", got, err, want)
+	}
+	masked := ""
+	upper := translatorFunc(func(text string) string {
+		masked = text
+		return strings.ToUpper(text)
+	})
+	got, err = TranslateMarkdown(upper, nil, "Ein Kong über den [Zaun](https://example.com/zaun) mit @max_m [[location:1|am Park]]", "de", "en", context.Background())
+	if want := "EIN Kong ÜBER DEN [ZAUN](https://example.com/zaun) MIT @max_m [[location:1|am Park]]"; err != nil || got != want {
+		t.Errorf("TranslateMarkdown() = %q, %v, want %q", got, err, want)
+	}
+	if want := `Ein <x id="3"/> über den [Zaun<x id="0"/> mit <x id="1"/> <x id="2"/>`; masked != want {
+		t.Errorf("TranslateMarkdown() sent %q, want %q", masked, want)
+	}
+	lossy := translatorFunc(func(text string) string { return "verloren" })
+	if _, err := TranslateMarkdown(lossy, nil, "Ein Kong", "de", "en", context.Background()); err == nil {
+		t.Errorf("TranslateMarkdown() error = nil for a translation losing a term")
+	}
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/graph"
+	"pkv/api/src/repository/t"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20  // results per page unless requested otherwise
+	MaxLimit     = 100 // maximum number of results per page
+)
+
+// snippetLength is the number of characters of the snippets, not counting the ellipses
+const snippetLength = 160
+
+type Service struct {
+	db *graph.Db
+}
+
+func NewService(db *graph.Db) *Service {
+	return &Service{db: db}
+}
+
+// position identifies the last result of a page, it is passed on as an opaque cursor
+type position struct {
+	Score float64 `json:"s"`
+	After string  `json:"a"`
+}
+
+// Search finds the entities of the given types, all types if none are given, whose descriptions in the language contain
+// the text. The cursor returned as Next of the previous page continues the search with the following page.
+func (s *Service) Search(text string, language string, types []string, cursor string, limit int, ctx context.Context) (domain.SearchResults, error) {
+	options, err := searchOptions(text, language, types, cursor, limit)
+	if err != nil {
+		return domain.SearchResults{}, err
+	}
+	options.Limit++ // one more to know if there is a following page
+	results, err := s.db.Search(options, ctx)
+	if err != nil {
+		return domain.SearchResults{}, t.Errorf("searching failed: %w", err)
+	}
+	return page(results, strings.Fields(text), options.Limit-1), nil
+}
+
+// searchOptions validates the parameters of a search
+func searchOptions(text string, language string, types []string, cursor string, limit int) (domain.SearchQueryOptions, error) {
+	options := domain.SearchQueryOptions{Text: strings.TrimSpace(text), Language: language, Limit: limit}
+	if options.Text == "" {
+		return options, t.Errorf("nothing to search for")
+	}
+	if options.Limit == 0 {
+		options.Limit = DefaultLimit
+	}
+	if options.Limit < 0 || options.Limit > MaxLimit {
+		return options, t.Errorf("the limit needs to be between 1 and %d", MaxLimit)
+	}
+	for _, kind := range types {
+		if _, ok := graph.SearchTypes[kind]; !ok {
+			return options, t.Errorf("unknown type %s", kind)
+		}
+		options.Types = append(options.Types, kind)
+	}
+	if len(options.Types) == 0 {
+		for kind := range graph.SearchTypes {
+			options.Types = append(options.Types, kind)
+		}
+	}
+	sort.Strings(options.Types)
+	if cursor != "" {
+		last, err := decodeCursor(cursor)
+		if err != nil {
+			return options, err
+		}
+		options.Score, options.After = last.Score, last.After
+	}
+	return options, nil
+}
+
+// page cuts the results down to the limit, sets the cursor of the following page and replaces the texts by snippets
+func page(results domain.SearchResults, words []string, limit int) domain.SearchResults {
+	if results.Results == nil {
+		results.Results = []domain.SearchResult{}
+	}
+	if results.Facets == nil {
+		results.Facets = map[string]int{}
+	}
+	if len(results.Results) > limit {
+		results.Results = results.Results[:limit]
+		last := results.Results[limit-1]
+		results.Next = encodeCursor(position{Score: last.Score, After: last.Type + "/" + last.Key})
+	}
+	for i := range results.Results {
+		results.Results[i].Snippet = Snippet(results.Results[i].Text, words, snippetLength)
+		results.Results[i].Text = ""
+	}
+	return results
+}
+
+func encodeCursor(last position) string {
+	data, _ := json.Marshal(last)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (position, error) {
+	var last position
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &last)
+	}
+	if err != nil || last.After == "" {
+		return last, t.Errorf("invalid cursor")
+	}
+	return last, nil
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"pkv/api/src/domain"
+	"reflect"
+	"testing"
+)
+
+func Test_searchOptions(t *testing.T) {
+	options, err := searchOptions(" backflip ", "de", nil, "", 0)
+	if err != nil {
+		t.Fatalf("searchOptions() error = %v", err)
+	}
+	if options.Text != "backflip" || options.Limit != DefaultLimit || !reflect.DeepEqual(options.Types, []string{"event", "location", "page", "training", "user"}) {
+		t.Errorf("searchOptions() = %+v, want all types and the default limit", options)
+	}
+	cursor := encodeCursor(position{Score: 1.25, After: "training/123"})
+	if options, err := searchOptions("backflip", "de", []string{"training"}, cursor, 5); err != nil || options.Score != 1.25 || options.After != "training/123" {
+		t.Errorf("searchOptions() = %+v, %v, want the position of the cursor", options, err)
+	}
+	for _, invalid := range []struct {
+		text   string
+		types  []string
+		cursor string
+		limit  int
+	}{
+		{"", nil, "", 0},
+		{"backflip", []string{"comment"}, "", 0},
+		{"backflip", nil, "not a cursor", 0},
+		{"backflip", nil, "", MaxLimit + 1},
+	} {
+		if _, err := searchOptions(invalid.text, "de", invalid.types, invalid.cursor, invalid.limit); err == nil {
+			t.Errorf("searchOptions(%+v) error = nil", invalid)
+		}
+	}
+}
+
+func Test_page(t *testing.T) {
+	results := domain.SearchResults{
+		Results: []domain.SearchResult{
+			{Type: "training", Key: "1", Text: "Backflip im Park", Score: 3},
+			{Type: "page", Key: "2", Score: 2},
+			{Type: "user", Key: "3", Score: 1},
+		},
+		Facets: map[string]int{"training": 1, "page": 1, "user": 1},
+	}
+	got := page(results, []string{"backflip"}, 2)
+	if len(got.Results) != 2 || got.Results[0].Snippet != "<mark>Backflip</mark> im Park" || got.Results[0].Text != "" {
+		t.Fatalf("page() = %+v, want two results with snippets instead of texts", got.Results)
+	}
+	if last, err := decodeCursor(got.Next); err != nil || last != (position{Score: 2, After: "page/2"}) {
+		t.Errorf("page() next = %v, %v, want the position of the second result", last, err)
+	}
+	if got := page(domain.SearchResults{}, nil, 2); got.Next != "" || got.Results == nil || got.Facets == nil {
+		t.Errorf("page() = %+v, want an empty last page", got)
+	}
+}
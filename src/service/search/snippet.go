@@ -0,0 +1,93 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Snippet returns an excerpt of about length characters of the text, starting shortly before the first occurrence of
+// any of the words. Words are found regardless of case and within compound words. The excerpt is HTML-escaped and every
+// occurrence is enclosed in a <mark> element, cut off text is indicated by ellipses.
+func Snippet(text string, words []string, length int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) == 0 {
+		return ""
+	}
+	matches := occurrences(runes, words)
+	start := 0
+	if len(matches) > 0 && matches[0][0] > length/4 {
+		start = matches[0][0] - length/4
+		for start < matches[0][0] && runes[start-1] != ' ' {
+			start++
+		}
+	}
+	end := min(start+length, len(runes))
+	if end < len(runes) {
+		for cut := end; cut > start; cut-- {
+			if runes[cut] == ' ' {
+				end = cut
+				break
+			}
+		}
+	}
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	from := start
+	for _, match := range matches {
+		if match[1] <= start || match[0] >= end {
+			continue
+		}
+		match[0], match[1] = max(match[0], start), min(match[1], end)
+		b.WriteString(html.EscapeString(string(runes[from:match[0]])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[match[0]:match[1]])))
+		b.WriteString("</mark>")
+		from = match[1]
+	}
+	b.WriteString(html.EscapeString(string(runes[from:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// occurrences returns the sorted and merged ranges of the runes at which any of the words occurs, ignoring case.
+// Single characters are ignored.
+func occurrences(runes []rune, words []string) [][2]int {
+	lower := []rune(strings.Map(unicode.ToLower, string(runes)))
+	var matches [][2]int
+	for _, word := range words {
+		pattern := []rune(strings.Map(unicode.ToLower, word))
+		if len(pattern) < 2 {
+			continue
+		}
+		for i := 0; i+len(pattern) <= len(lower); i++ {
+			if equal(lower[i:i+len(pattern)], pattern) {
+				matches = append(matches, [2]int{i, i + len(pattern)})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+	var merged [][2]int
+	for _, match := range matches {
+		if n := len(merged); n > 0 && match[0] <= merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], match[1])
+			continue
+		}
+		merged = append(merged, match)
+	}
+	return merged
+}
+
+func equal(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
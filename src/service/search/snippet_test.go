@@ -0,0 +1,29 @@
+package search
+
+import "testing"
+
+func TestSnippet(t *testing.T) {
+	long := "Wir treffen uns jeden Freitag am Stadtpark. Dort üben wir Präzisionssprünge, Wallruns und Katzensprünge. " +
+		"Im Anschluss gibt es ein freies Parkourtraining für alle, die noch Lust haben."
+	tests := []struct {
+		name   string
+		text   string
+		words  []string
+		length int
+		want   string
+	}{
+		{"short", "Parkour im Park", []string{"park"}, 160, "<mark>Park</mark>our im <mark>Park</mark>"},
+		{"escaped", "Tom & Jerry <3 Parkour", []string{"parkour"}, 160, "Tom &amp; Jerry &lt;3 <mark>Parkour</mark>"},
+		{"without match", "Jeden Freitag im Park", []string{"jam"}, 12, "Jeden…"},
+		{"compound", long, []string{"TRAINING"}, 40, "…Parkour<mark>training</mark> für alle, die noch Lust…"},
+		{"umlauts", "Präzisionssprünge üben", []string{"SPRÜNGE"}, 160, "Präzisions<mark>sprünge</mark> üben"},
+		{"empty", "", []string{"park"}, 160, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Snippet(tt.text, tt.words, tt.length); got != tt.want {
+				t.Errorf("Snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
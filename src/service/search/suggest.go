@@ -0,0 +1,45 @@
+package search
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/t"
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultSuggestions = 10 // suggestions returned unless requested otherwise
+	MaxSuggestions     = 50 // maximum number of suggestions
+	minSuggestLength   = 2  // letters or digits needed before suggestions are made
+)
+
+// Suggest completes the text with names of users, cities of locations and titles in the language. Texts with fewer
+// than two letters or digits get no suggestions, as they would match almost everything.
+func (s *Service) Suggest(text string, language string, limit int, ctx context.Context) ([]domain.Suggestion, error) {
+	if limit == 0 {
+		limit = DefaultSuggestions
+	}
+	if limit < 0 || limit > MaxSuggestions {
+		return nil, t.Errorf("the limit needs to be between 1 and %d", MaxSuggestions)
+	}
+	if !suggestible(text) {
+		return []domain.Suggestion{}, nil
+	}
+	suggestions, err := s.db.Suggest(strings.TrimSpace(text), language, limit, ctx)
+	if err != nil {
+		return nil, t.Errorf("suggesting failed: %w", err)
+	}
+	return suggestions, nil
+}
+
+// suggestible reports whether the text contains enough letters or digits to suggest completions
+func suggestible(text string) bool {
+	count := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			count++
+		}
+	}
+	return count >= minSuggestLength
+}
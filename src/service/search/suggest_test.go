@@ -0,0 +1,22 @@
+package search
+
+import "testing"
+
+func Test_suggestible(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"", false},
+		{"h", false},
+		{" - ", false},
+		{"ha", true},
+		{"St. P", true},
+		{"汉堡", true},
+	}
+	for _, tt := range tests {
+		if got := suggestible(tt.text); got != tt.want {
+			t.Errorf("suggestible(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
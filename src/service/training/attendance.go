@@ -0,0 +1,182 @@
+package training
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/graph"
+	"pkv/api/src/repository/t"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordAttendance stores who took part in the occurrence of the training on the date of the list, replacing the list
+// recorded before. Attendance can only be recorded once the occurrence has begun.
+func (s *Service) RecordAttendance(training domain.TrainingDTO, list *domain.AttendanceList, ctx context.Context) error {
+	if err := checkBegun(training, list.Date, time.Now()); err != nil {
+		return err
+	}
+	if list.Anonymous < 0 {
+		return t.Errorf("the number of anonymous participants cannot be negative")
+	}
+	list.TrainingKey = training.Key
+	list.UserKeys = unique(list.UserKeys)
+	for _, key := range list.UserKeys {
+		exists, err := s.db.Users.Has(key, ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return t.Errorf("user %s not found", key)
+		}
+	}
+	if err := s.db.SaveAttendanceList(list, ctx); err != nil {
+		return t.Errorf("saving attendance list failed: %w", err)
+	}
+	return nil
+}
+
+// ReadAttendance returns the attendance recorded for the occurrence of the training on the given date, which is empty
+// if nothing has been recorded yet
+func (s *Service) ReadAttendance(training domain.TrainingDTO, date time.Time, ctx context.Context) (*domain.AttendanceList, error) {
+	key := graph.AttendanceListKey(training.Key, date)
+	exists, err := s.db.Attendances.Has(key, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return &domain.AttendanceList{TrainingKey: training.Key, Date: date, UserKeys: []string{}}, nil
+	}
+	return s.db.Attendances.Read(key, ctx)
+}
+
+// AttendanceReport aggregates the attendance recorded for the trainings between from (inclusive) and to (exclusive)
+func (s *Service) AttendanceReport(trainings []domain.TrainingDTO, from, to time.Time, language string, ctx context.Context) (domain.AttendanceReport, error) {
+	if err := checkTimespan(from, to); err != nil {
+		return domain.AttendanceReport{}, err
+	}
+	keys := make([]string, 0, len(trainings))
+	for _, training := range trainings {
+		keys = append(keys, training.Key)
+	}
+	lists, err := s.db.GetAttendanceLists(keys, from, to, ctx)
+	if err != nil {
+		return domain.AttendanceReport{}, t.Errorf("reading attendance lists failed: %w", err)
+	}
+	return aggregate(trainings, lists, from, to, language)
+}
+
+// aggregate sums up the attendance lists per training and in total. Every training is listed along with the number of
+// occurrences scheduled between from (inclusive) and to (exclusive), even if no attendance has been recorded.
+func aggregate(trainings []domain.TrainingDTO, lists []domain.AttendanceList, from, to time.Time, language string) (domain.AttendanceReport, error) {
+	report := domain.AttendanceReport{From: from, To: to.AddDate(0, 0, -1), Trainings: []domain.TrainingAttendance{}}
+	listsOf := make(map[string][]domain.AttendanceList)
+	for _, list := range lists {
+		listsOf[list.TrainingKey] = append(listsOf[list.TrainingKey], list)
+	}
+	members := make(map[string]bool)
+	for _, training := range trainings {
+		exceptions, err := withHolidays(training.Training, from, to)
+		if err != nil {
+			return report, err
+		}
+		title, _ := describe(training.Descriptions, language)
+		row := domain.TrainingAttendance{
+			Training:    domain.TrainingSummary{Key: training.Key, Type: training.Type, Title: title},
+			Occurrences: len(calendar.ComputeOccurrences(training.Cycles, exceptions, from, to, Zone(training))),
+		}
+		trainingMembers := make(map[string]bool)
+		for _, list := range listsOf[training.Key] {
+			row.Recorded++
+			row.Participants += len(list.UserKeys) + list.Anonymous
+			row.Anonymous += list.Anonymous
+			for _, key := range list.UserKeys {
+				trainingMembers[key] = true
+				members[key] = true
+			}
+		}
+		row.Members = len(trainingMembers)
+		row.Average = average(row.Participants, row.Recorded)
+		report.Trainings = append(report.Trainings, row)
+
+		report.Total.Occurrences += row.Occurrences
+		report.Total.Recorded += row.Recorded
+		report.Total.Participants += row.Participants
+		report.Total.Anonymous += row.Anonymous
+	}
+	report.Total.Members = len(members)
+	report.Total.Average = average(report.Total.Participants, report.Total.Recorded)
+	return report, nil
+}
+
+// ReportCSV exports the report with one line per training, followed by the total
+func ReportCSV(report domain.AttendanceReport) (string, error) {
+	var csvData strings.Builder
+	writer := csv.NewWriter(&csvData)
+	if err := writer.Write([]string{"Training", "Title", "From", "To", "Occurrences", "Recorded", "Participants", "Members", "Anonymous", "Average"}); err != nil {
+		return "", err
+	}
+	rows := append(append([]domain.TrainingAttendance{}, report.Trainings...), report.Total)
+	for i, row := range rows {
+		key := row.Training.Key
+		if i == len(rows)-1 {
+			key = "Total"
+		}
+		if err := writer.Write([]string{
+			key,
+			row.Training.Title,
+			report.From.Format(time.DateOnly),
+			report.To.Format(time.DateOnly),
+			strconv.Itoa(row.Occurrences),
+			strconv.Itoa(row.Recorded),
+			strconv.Itoa(row.Participants),
+			strconv.Itoa(row.Members),
+			strconv.Itoa(row.Anonymous),
+			fmt.Sprintf("%.1f", row.Average),
+		}); err != nil {
+			return "", err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+	return csvData.String(), nil
+}
+
+// checkBegun makes sure the training takes place on the given date and its first occurrence on that day has begun
+func checkBegun(training domain.TrainingDTO, date time.Time, now time.Time) error {
+	occurrences, err := occurrencesOn(training, date)
+	if err != nil {
+		return err
+	}
+	if occurrences[0].Start.After(now) {
+		return t.Errorf("the training on %s has not begun yet", date.Format(time.DateOnly))
+	}
+	return nil
+}
+
+func average(participants int, occurrences int) float64 {
+	if occurrences == 0 {
+		return 0
+	}
+	return float64(participants) / float64(occurrences)
+}
+
+// unique returns the keys sorted and without duplicates
+func unique(keys []string) []string {
+	result := make([]string, 0, len(keys))
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
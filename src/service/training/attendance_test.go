@@ -0,0 +1,71 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_aggregate(t *testing.T) {
+	friday := func(week int) time.Time { return time.Date(2024, 4, 5+7*week, 0, 0, 0, 0, time.UTC) }
+	trainings := []domain.TrainingDTO{
+		{Training: domain.Training{
+			Entity:       domain.Entity{Key: "1"},
+			Descriptions: domain.Descriptions{"de": {Title: "Parkour im Park"}},
+			Cycles:       []domain.Cycle{{Weekday: 5, Begin: 18 * 3600, Duration: 7200}},
+		}},
+		{Training: domain.Training{
+			Entity: domain.Entity{Key: "2"},
+			Cycles: []domain.Cycle{{Weekday: 6, Begin: 10 * 3600, Duration: 7200}},
+		}},
+	}
+	lists := []domain.AttendanceList{
+		{TrainingKey: "1", Date: friday(0), UserKeys: []string{"anna", "ben"}, Anonymous: 3},
+		{TrainingKey: "1", Date: friday(1), UserKeys: []string{"anna"}, Anonymous: 0},
+		{TrainingKey: "2", Date: friday(1).AddDate(0, 0, 1), UserKeys: []string{"ben", "carla"}, Anonymous: 1},
+	}
+	report, err := aggregate(trainings, lists, friday(0), friday(4), "de")
+	if err != nil {
+		t.Fatalf("aggregate() error = %v", err)
+	}
+	first := report.Trainings[0]
+	if first.Training.Title != "Parkour im Park" || first.Occurrences != 4 || first.Recorded != 2 || first.Participants != 6 || first.Members != 2 || first.Anonymous != 3 || first.Average != 3 {
+		t.Errorf("aggregate() first training = %+v", first)
+	}
+	total := report.Total
+	if total.Occurrences != 8 || total.Recorded != 3 || total.Participants != 9 || total.Members != 3 || total.Anonymous != 4 {
+		t.Errorf("aggregate() total = %+v", total)
+	}
+	if !report.To.Equal(friday(4).AddDate(0, 0, -1)) {
+		t.Errorf("aggregate() to = %v, want the inclusive end", report.To)
+	}
+
+	csv, err := ReportCSV(report)
+	if err != nil {
+		t.Fatalf("ReportCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("ReportCSV() returned %d lines, want 4", len(lines))
+	}
+	if want := "1,Parkour im Park,2024-04-05,2024-05-02,4,2,6,2,3,3.0"; lines[1] != want {
+		t.Errorf("ReportCSV() line 2 = %v, want %v", lines[1], want)
+	}
+	if !strings.HasPrefix(lines[3], "Total,") {
+		t.Errorf("ReportCSV() last line = %v, want the total", lines[3])
+	}
+}
+
+func Test_checkBegun(t *testing.T) {
+	training := domain.TrainingDTO{Training: domain.Training{
+		Cycles: []domain.Cycle{{Weekday: 5, Begin: 18 * 3600, Duration: 7200}},
+	}}
+	friday := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+	if err := checkBegun(training, friday, friday.Add(19*time.Hour)); err != nil {
+		t.Errorf("checkBegun() error = %v", err)
+	}
+	if err := checkBegun(training, friday, friday.Add(15*time.Hour)); err == nil {
+		t.Errorf("checkBegun() accepted an occurrence that has not begun")
+	}
+}
@@ -0,0 +1,122 @@
+package training
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/t"
+	"sort"
+	"time"
+)
+
+// CalendarEntries expands the occurrences of all trainings matching the options between from (inclusive) and to
+// (exclusive) into one chronological list, which also contains the events matching the options as single entries.
+// Skip and Limit of the options apply to the entries, not to the trainings. The stored occurrences are used if they
+// cover the time span, otherwise the cycles are expanded on the fly.
+func (s *Service) CalendarEntries(options domain.TrainingQueryOptions, from, to time.Time, ctx context.Context) ([]domain.CalendarEntry, error) {
+	if err := checkTimespan(from, to); err != nil {
+		return nil, err
+	}
+	skip, limit := options.Skip, options.Limit
+	options.Skip, options.Limit = 0, 0
+	trainings, err := s.FilterTrainings(options, ctx)
+	if err != nil {
+		return nil, err
+	}
+	window, err := s.db.GetOccurrenceWindow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var entries []domain.CalendarEntry
+	if window.Covers(from, to) {
+		entries, err = s.storedEntries(trainings, from, to, options.Language, ctx)
+	} else {
+		entries, err = s.computedEntries(trainings, from, to, options.Language, ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.FilterEvents(options, from, to, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		entries = append(entries, eventEntry(event, options.Language))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Start.Equal(entries[j].Start) {
+			return entries[i].Training.Key < entries[j].Training.Key
+		}
+		return entries[i].Start.Before(entries[j].Start)
+	})
+	return paginate(entries, skip, limit), nil
+}
+
+// computedEntries expands the cycles of the trainings into calendar entries
+func (s *Service) computedEntries(trainings []domain.TrainingDTO, from, to time.Time, language string, ctx context.Context) ([]domain.CalendarEntry, error) {
+	resolver := s.newLocationResolver()
+	entries := []domain.CalendarEntry{}
+	for _, training := range trainings {
+		exceptions, err := withHolidays(training.Training, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range calendar.ComputeOccurrences(training.Cycles, exceptions, from, to, Zone(training)) {
+			entry, err := newEntry(occurrence, training, language, resolver, ctx)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// storedEntries reads the occurrences of the trainings from the occurrences collection, which must cover the time span
+func (s *Service) storedEntries(trainings []domain.TrainingDTO, from, to time.Time, language string, ctx context.Context) ([]domain.CalendarEntry, error) {
+	byKey := make(map[string]domain.TrainingDTO, len(trainings))
+	keys := make([]string, 0, len(trainings))
+	for _, training := range trainings {
+		byKey[training.Key] = training
+		keys = append(keys, training.Key)
+	}
+	occurrences, err := s.db.GetStoredOccurrences(keys, from, to, ctx)
+	if err != nil {
+		return nil, t.Errorf("reading stored occurrences failed: %w", err)
+	}
+	resolver := s.newLocationResolver()
+	entries := []domain.CalendarEntry{}
+	for _, occurrence := range occurrences {
+		entry, err := newEntry(occurrence.Occurrence, byKey[occurrence.TrainingKey], language, resolver, ctx)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func newEntry(occurrence domain.Occurrence, training domain.TrainingDTO, language string, resolver *locationResolver, ctx context.Context) (domain.CalendarEntry, error) {
+	title, _ := describe(training.Descriptions, language)
+	location, err := resolver.resolve(occurrence.LocationId, training.Location, ctx)
+	if err != nil {
+		return domain.CalendarEntry{}, err
+	}
+	return domain.CalendarEntry{
+		Occurrence: occurrence,
+		Training:   domain.TrainingSummary{Key: training.Key, Type: training.Type, Title: title},
+		Location:   location,
+	}, nil
+}
+
+// paginate returns at most limit items after skipping the given number, a limit of 0 returns all remaining items
+func paginate[T any](items []T, skip, limit int) []T {
+	if skip >= len(items) {
+		return items[:0]
+	}
+	items = items[max(skip, 0):]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
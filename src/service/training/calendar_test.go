@@ -0,0 +1,30 @@
+package training
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_paginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name  string
+		skip  int
+		limit int
+		want  []int
+	}{
+		{"everything", 0, 0, []int{1, 2, 3, 4, 5}},
+		{"first page", 0, 2, []int{1, 2}},
+		{"second page", 2, 2, []int{3, 4}},
+		{"last page", 4, 2, []int{5}},
+		{"beyond the end", 5, 2, []int{}},
+		{"remaining items", 3, 0, []int{4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paginate(items, tt.skip, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paginate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package training
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/t"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AssignCoaches makes the users the coaches of the occurrence of the training on the given date, replacing the
+// organisers on that day. Without any users, the organisers are responsible again. It returns the effective coaches.
+func (s *Service) AssignCoaches(training domain.TrainingDTO, date time.Time, userKeys []string, ctx context.Context) ([]domain.User, error) {
+	if _, err := occurrencesOn(training, date); err != nil {
+		return nil, err
+	}
+	userKeys = unique(userKeys)
+	for _, key := range userKeys {
+		exists, err := s.db.Users.Has(key, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, t.Errorf("user %s not found", key)
+		}
+	}
+	if err := s.db.ReplaceCoaches(training.Key, date, userKeys, ctx); err != nil {
+		return nil, t.Errorf("assigning coaches failed: %w", err)
+	}
+	coachings, err := s.db.GetCoachings(training.Key, date, date.AddDate(0, 0, 1), ctx)
+	if err != nil {
+		return nil, t.Errorf("reading coaches failed: %w", err)
+	}
+	return effectiveCoaches(coachesByDate(coachings), training.Organisers, date), nil
+}
+
+// Duties lists the occurrences between from (inclusive) and to (exclusive) the user is responsible for, as organiser
+// of a training without substitutes on that day or as substitute coach
+func (s *Service) Duties(userKey string, from, to time.Time, language string, ctx context.Context) ([]domain.Duty, error) {
+	if err := checkTimespan(from, to); err != nil {
+		return nil, err
+	}
+	trainings, err := s.FilterTrainings(domain.TrainingQueryOptions{OrganiserKey: userKey}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	substitutions, err := s.db.GetCoachingsOfUser(userKey, from, to, ctx)
+	if err != nil {
+		return nil, t.Errorf("reading coaching duties failed: %w", err)
+	}
+	for _, substitution := range substitutions {
+		key := strings.TrimPrefix(substitution.To, "trainings/")
+		if slices.ContainsFunc(trainings, func(training domain.TrainingDTO) bool { return training.Key == key }) {
+			continue
+		}
+		training, err := s.ReadTraining(key, ctx)
+		if err != nil {
+			return nil, err
+		}
+		trainings = append(trainings, training)
+	}
+	resolver := s.newLocationResolver()
+	duties := []domain.Duty{}
+	for _, training := range trainings {
+		exceptions, err := withHolidays(training.Training, from, to)
+		if err != nil {
+			return nil, err
+		}
+		coachings, err := s.db.GetCoachings(training.Key, from, to, ctx)
+		if err != nil {
+			return nil, t.Errorf("reading coaches failed: %w", err)
+		}
+		occurrences := calendar.ComputeOccurrences(training.Cycles, exceptions, from, to, Zone(training))
+		for _, duty := range dutiesOf(userKey, training, occurrences, coachesByDate(coachings), language) {
+			location, err := resolver.resolve(duty.LocationId, training.Location, ctx)
+			if err != nil {
+				return nil, err
+			}
+			duty.Location = location
+			duties = append(duties, duty)
+		}
+	}
+	sort.SliceStable(duties, func(i, j int) bool {
+		return duties[i].Start.Before(duties[j].Start)
+	})
+	return duties, nil
+}
+
+// dutiesOf returns the occurrences of the training the user coaches
+func dutiesOf(userKey string, training domain.TrainingDTO, occurrences []domain.Occurrence, coaches map[string][]domain.User, language string) []domain.Duty {
+	title, _ := describe(training.Descriptions, language)
+	summary := domain.TrainingSummary{Key: training.Key, Type: training.Type, Title: title}
+	organiser := slices.Contains(training.OrganiserKeys, userKey)
+	var duties []domain.Duty
+	for _, occurrence := range occurrences {
+		substitutes, substituted := coaches[occurrence.Date.Format(time.DateOnly)]
+		if substituted {
+			if !slices.ContainsFunc(substitutes, func(user domain.User) bool { return user.Key == userKey }) {
+				continue
+			}
+		} else if !organiser {
+			continue
+		}
+		duties = append(duties, domain.Duty{
+			CalendarEntry: domain.CalendarEntry{Occurrence: occurrence, Training: summary},
+			Substitute:    substituted && !organiser,
+		})
+	}
+	return duties
+}
+
+// coachesByDate groups the assigned coaches by the date in the format YYYY-MM-DD
+func coachesByDate(coachings []domain.CoachingDTO) map[string][]domain.User {
+	coaches := make(map[string][]domain.User)
+	for _, coaching := range coachings {
+		user := domain.User{Entity: domain.Key(strings.TrimPrefix(coaching.From, "users/"))}
+		if coaching.User != nil {
+			user = *coaching.User
+		}
+		date := coaching.Date.Format(time.DateOnly)
+		coaches[date] = append(coaches[date], user)
+	}
+	return coaches
+}
+
+// effectiveCoaches returns the coaches assigned to the date, or the organisers if there are none
+func effectiveCoaches(coaches map[string][]domain.User, organisers []domain.User, date time.Time) []domain.User {
+	if assigned, ok := coaches[date.Format(time.DateOnly)]; ok {
+		return assigned
+	}
+	return organisers
+}
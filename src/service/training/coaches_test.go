@@ -0,0 +1,42 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func Test_dutiesOf(t *testing.T) {
+	friday := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+	training := domain.TrainingDTO{
+		Training:      domain.Training{Entity: domain.Key("1")},
+		OrganiserKeys: []string{"anna"},
+		Organisers:    []domain.User{{Entity: domain.Key("anna")}},
+	}
+	var occurrences []domain.Occurrence
+	for week := 0; week < 3; week++ {
+		occurrences = append(occurrences, domain.Occurrence{Date: friday.AddDate(0, 0, 7*week)})
+	}
+	coaches := coachesByDate([]domain.CoachingDTO{
+		{Coaching: domain.Coaching{From: "users/ben", Date: friday.AddDate(0, 0, 7)}},
+	})
+
+	if got := effectiveCoaches(coaches, training.Organisers, friday.AddDate(0, 0, 7)); len(got) != 1 || got[0].Key != "ben" {
+		t.Errorf("effectiveCoaches() = %+v, want ben", got)
+	}
+	if got := effectiveCoaches(coaches, training.Organisers, friday); len(got) != 1 || got[0].Key != "anna" {
+		t.Errorf("effectiveCoaches() = %+v, want the organiser anna", got)
+	}
+
+	anna := dutiesOf("anna", training, occurrences, coaches, "de")
+	if len(anna) != 2 || anna[0].Substitute || !anna[1].Date.Equal(friday.AddDate(0, 0, 14)) {
+		t.Errorf("dutiesOf(anna) = %+v, want the first and third Friday", anna)
+	}
+	ben := dutiesOf("ben", training, occurrences, coaches, "de")
+	if len(ben) != 1 || !ben[0].Substitute || ben[0].Training.Key != "1" {
+		t.Errorf("dutiesOf(ben) = %+v, want the second Friday as substitute", ben)
+	}
+	if carla := dutiesOf("carla", training, occurrences, coaches, "de"); len(carla) != 0 {
+		t.Errorf("dutiesOf(carla) = %+v, want none", carla)
+	}
+}
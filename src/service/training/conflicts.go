@@ -0,0 +1,196 @@
+package training
+
+import (
+	"context"
+	"log"
+	"pkv/api/src/api"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/dpv"
+	"pkv/api/src/repository/t"
+	"sort"
+	"time"
+)
+
+// ConflictDays is the number of days from today checked for conflicts when a training is written
+const ConflictDays = 180
+
+// Conflicts returns the overlaps between occurrences of different trainings at the same location between from
+// (inclusive) and to (exclusive)
+func (s *Service) Conflicts(from, to time.Time, ctx context.Context) ([]domain.Conflict, error) {
+	if err := checkTimespan(from, to); err != nil {
+		return nil, err
+	}
+	trainings, err := s.FilterTrainings(domain.TrainingQueryOptions{}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := locatedEntries(trainings, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return findConflicts(entries), nil
+}
+
+// ConflictsOf returns the overlaps the training would have with other trainings happening at the same locations
+// within the next ConflictDays days. The training replaces its stored version, if any. It takes place at the location
+// of the given key, or else at the location of its stored version, as new trainings are linked to their location
+// only after they have been created.
+func (s *Service) ConflictsOf(training domain.Training, locationKey string, ctx context.Context) ([]domain.Conflict, error) {
+	candidate := domain.TrainingDTO{Training: training}
+	if locationKey != "" {
+		location, err := s.db.Locations.Read(locationKey, ctx)
+		if err != nil {
+			return nil, t.Errorf("reading location failed: %w", err)
+		}
+		candidate.Location, candidate.LocationKey = location, location.Key
+	} else if training.Key != "" {
+		stored, err := s.ReadTraining(training.Key, ctx)
+		if err == nil {
+			candidate.Location, candidate.LocationKey = stored.Location, stored.LocationKey
+		}
+	}
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	to := from.AddDate(0, 0, ConflictDays)
+	entries, err := locatedEntries([]domain.TrainingDTO{candidate}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	locations := make(map[string]bool)
+	for _, entry := range entries {
+		locations[entry.LocationId] = true
+	}
+	for location := range locations {
+		others, err := s.FilterTrainings(domain.TrainingQueryOptions{LocationKey: location}, ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(others); i++ {
+			if others[i].Key == training.Key {
+				others = append(others[:i], others[i+1:]...)
+				i--
+			}
+		}
+		more, err := locatedEntries(others, from, to)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, more...)
+	}
+	var conflicts []domain.Conflict
+	for _, conflict := range findConflicts(entries) {
+		if conflict.Entries[0].Training.Key == training.Key || conflict.Entries[1].Training.Key == training.Key {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts, nil
+}
+
+// ConflictHook reports conflicts before trainings are written through the generic CRUD endpoints, see checkConflicts
+func (s *Service) ConflictHook(training *domain.Training, ctx context.Context) error {
+	return s.checkConflicts(training, "", ctx)
+}
+
+// checkConflicts reports the conflicts of a training about to be written at the location of the given key, or else
+// at its stored location. The first one fails the write if the write options of the context reject conflicts, which
+// defaults to the reject_conflicts setting. Otherwise they are returned to the client as warnings, or only logged for
+// writes without options.
+func (s *Service) checkConflicts(training *domain.Training, locationKey string, ctx context.Context) error {
+	conflicts, err := s.ConflictsOf(*training, locationKey, ctx)
+	if err != nil {
+		return err
+	}
+	options := api.WriteOptionsOf(ctx)
+	reject := rejectsConflicts(ctx)
+	for _, conflict := range conflicts {
+		err := t.Errorf("the training overlaps with training %s at location %s on %s", otherTraining(conflict, training.Key), conflict.LocationKey, conflict.Start.Format(time.DateTime))
+		switch {
+		case reject:
+			return err
+		case options != nil:
+			options.Warnings = append(options.Warnings, err)
+		default:
+			log.Printf("training %s: %v", training.Key, err)
+		}
+	}
+	return nil
+}
+
+// rejectsConflicts tells whether the write options of the context reject conflicts, or else the reject_conflicts
+// setting does
+func rejectsConflicts(ctx context.Context) bool {
+	if options := api.WriteOptionsOf(ctx); options != nil {
+		return options.RejectConflicts
+	}
+	return dpv.ConfigInstance != nil && dpv.ConfigInstance.Settings.RejectConflicts
+}
+
+func otherTraining(conflict domain.Conflict, key string) string {
+	if conflict.Entries[0].Training.Key == key {
+		return conflict.Entries[1].Training.Key
+	}
+	return conflict.Entries[0].Training.Key
+}
+
+// locatedEntries expands the trainings into calendar entries whose LocationId is the key of the location they take
+// place at. Occurrences without a location are left out.
+func locatedEntries(trainings []domain.TrainingDTO, from, to time.Time) ([]domain.CalendarEntry, error) {
+	var entries []domain.CalendarEntry
+	for _, training := range trainings {
+		exceptions, err := withHolidays(training.Training, from, to)
+		if err != nil {
+			return nil, err
+		}
+		summary := domain.TrainingSummary{Key: training.Key, Type: training.Type}
+		for _, occurrence := range calendar.ComputeOccurrences(training.Cycles, exceptions, from, to, Zone(training)) {
+			occurrence.LocationId = LocationKey(occurrence.LocationId)
+			if occurrence.LocationId == "" {
+				occurrence.LocationId = training.LocationKey
+			}
+			if occurrence.LocationId != "" {
+				entries = append(entries, domain.CalendarEntry{Occurrence: occurrence, Training: summary})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// findConflicts returns every pair of entries of different trainings at the same location whose time windows overlap,
+// sorted by the beginning of the overlap. Entries without a duration overlap with those beginning at the same time.
+func findConflicts(entries []domain.CalendarEntry) []domain.Conflict {
+	byLocation := make(map[string][]domain.CalendarEntry)
+	for _, entry := range entries {
+		byLocation[entry.LocationId] = append(byLocation[entry.LocationId], entry)
+	}
+	conflicts := []domain.Conflict{}
+	for location, entries := range byLocation {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Start.Before(entries[j].Start) })
+		for i, first := range entries {
+			for _, second := range entries[i+1:] {
+				if second.Start.After(first.Start) && !second.Start.Before(first.End) {
+					break
+				}
+				if first.Training.Key == second.Training.Key {
+					continue
+				}
+				end := first.End
+				if second.End.Before(end) {
+					end = second.End
+				}
+				conflicts = append(conflicts, domain.Conflict{
+					LocationKey: location,
+					Start:       second.Start,
+					End:         end,
+					Entries:     []domain.CalendarEntry{first, second},
+				})
+			}
+		}
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		if conflicts[i].Start.Equal(conflicts[j].Start) {
+			return conflicts[i].LocationKey < conflicts[j].LocationKey
+		}
+		return conflicts[i].Start.Before(conflicts[j].Start)
+	})
+	return conflicts
+}
@@ -0,0 +1,42 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func Test_findConflicts(t *testing.T) {
+	friday := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+	training := func(key string, location string, begin int, exceptions ...domain.Exception) domain.TrainingDTO {
+		return domain.TrainingDTO{
+			Training: domain.Training{
+				Entity:     domain.Key(key),
+				Cycles:     []domain.Cycle{{Weekday: 5, Begin: begin, Duration: 7200}},
+				Exceptions: exceptions,
+				Timezone:   "Europe/Berlin",
+			},
+			LocationKey: location,
+		}
+	}
+	trainings := []domain.TrainingDTO{
+		training("1", "hall", 18*3600),
+		training("2", "hall", 19*3600, domain.Exception{Date: friday.AddDate(0, 0, 7), Begin: 20 * 3600, Duration: 3600}),
+		training("3", "park", 18*3600),
+		training("4", "hall", 10*3600, domain.Exception{Date: friday, Begin: 18 * 3600, Duration: 3600, LocationId: "location/park"}),
+	}
+	entries, err := locatedEntries(trainings, friday, friday.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatalf("locatedEntries() error = %v", err)
+	}
+	conflicts := findConflicts(entries)
+	if len(conflicts) != 2 {
+		t.Fatalf("findConflicts() = %+v, want 2 conflicts", conflicts)
+	}
+	if got := conflicts[0]; got.LocationKey != "park" || got.Start.Hour() != 18 || got.End.Hour() != 19 {
+		t.Errorf("findConflicts() first = %+v, want trainings 3 and 4 in the park from 18:00 to 19:00", got)
+	}
+	if got := conflicts[1]; got.LocationKey != "hall" || got.Start.Hour() != 19 || got.End.Hour() != 20 || got.Entries[0].Training.Key != "1" || got.Entries[1].Training.Key != "2" {
+		t.Errorf("findConflicts() second = %+v, want trainings 1 and 2 in the hall from 19:00 to 20:00", got)
+	}
+}
@@ -0,0 +1,138 @@
+package training
+
+import (
+	"context"
+	"fmt"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/ical"
+	"pkv/api/src/repository/t"
+	"regexp"
+	"time"
+)
+
+// currency matches ISO 4217 codes such as EUR
+var currency = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidateEvent returns the first problem found in the dates, limits and price of an event, if any
+func ValidateEvent(event *domain.Event) error {
+	if event.Start.IsZero() || event.End.IsZero() {
+		return t.Errorf("an event needs a start and an end")
+	}
+	if !event.End.After(event.Start) {
+		return t.Errorf("the end of the event needs to be after its start")
+	}
+	if event.RegistrationDeadline != nil && event.RegistrationDeadline.After(event.End) {
+		return t.Errorf("the registration deadline cannot be after the end of the event")
+	}
+	if event.ParticipantLimit < 0 {
+		return t.Errorf("the participant limit cannot be negative")
+	}
+	if event.Price < 0 {
+		return t.Errorf("the price cannot be negative")
+	}
+	if event.Currency != "" && !currency.MatchString(event.Currency) {
+		return t.Errorf("invalid currency %s", event.Currency)
+	}
+	return nil
+}
+
+// ValidateEventHook calls ValidateEvent before events are written through the generic CRUD endpoints
+func ValidateEventHook(event *domain.Event, ctx context.Context) error {
+	return ValidateEvent(event)
+}
+
+// ReadEvent reads an event including the locations it takes place at and its organisers
+func (s *Service) ReadEvent(key string, ctx context.Context) (domain.EventDTO, error) {
+	events, err := s.db.GetFilteredEvents(domain.TrainingQueryOptions{
+		Key:     key,
+		Include: includeCalendar(nil),
+	}, time.Time{}, time.Time{}, ctx)
+	if err != nil {
+		return domain.EventDTO{}, t.Errorf("read event failed: %w", err)
+	}
+	if len(events) == 0 {
+		return domain.EventDTO{}, t.Errorf("event %s not found", key)
+	}
+	return events[0], nil
+}
+
+// FilterEvents reads the events matching the filters of the trainings which overlap with the time span between from
+// and to, including their locations and organisers. A zero from or to leaves the time span open on that side.
+func (s *Service) FilterEvents(options domain.TrainingQueryOptions, from, to time.Time, ctx context.Context) ([]domain.EventDTO, error) {
+	options.Include = includeCalendar(options.Include)
+	events, err := s.db.GetFilteredEvents(options, from, to, ctx)
+	if err != nil {
+		return nil, t.Errorf("querying events failed: %w", err)
+	}
+	return events, nil
+}
+
+// SetEventLocations links an event to the locations it takes place at, replacing the previous ones
+func (s *Service) SetEventLocations(key string, locationKeys []string, ctx context.Context) error {
+	if ok, err := s.db.Events.Has(key, ctx); err != nil {
+		return err
+	} else if !ok {
+		return t.Errorf("event %s not found", key)
+	}
+	for _, locationKey := range locationKeys {
+		if ok, err := s.db.Locations.Has(locationKey, ctx); err != nil {
+			return err
+		} else if !ok {
+			return t.Errorf("location %s not found", locationKey)
+		}
+	}
+	return s.db.ReplaceEventLocations(key, unique(locationKeys), ctx)
+}
+
+// EventZone returns the time zone of an event, falling back to the one of its first location
+func EventZone(event domain.EventDTO) *time.Location {
+	if len(event.Locations) > 0 {
+		return calendar.Zone(event.Timezone, event.Locations[0].Timezone)
+	}
+	return calendar.Zone(event.Timezone)
+}
+
+// eventEntry turns an event into a single calendar entry lasting from its start until its end, which takes place at
+// the first of its locations
+func eventEntry(event domain.EventDTO, language string) domain.CalendarEntry {
+	zone := EventZone(event)
+	start, end := event.Start.In(zone), event.End.In(zone)
+	title, _ := describe(event.Descriptions, language)
+	entry := domain.CalendarEntry{
+		Occurrence: domain.Occurrence{
+			Date:     civilDate(start),
+			Begin:    start.Hour()*3600 + start.Minute()*60 + start.Second(),
+			Duration: int(end.Sub(start).Seconds()),
+			Start:    start,
+			End:      end,
+		},
+		Training: domain.TrainingSummary{Key: event.Key, Type: event.Type, Title: title, Event: true},
+	}
+	if len(event.Locations) > 0 {
+		entry.Location = &event.Locations[0]
+	}
+	return entry
+}
+
+// eventFeed turns an event into a single iCalendar event
+func eventFeed(event domain.EventDTO, language string) ical.Event {
+	zone := EventZone(event)
+	summary, text := describe(event.Descriptions, language)
+	stamp := event.Modified
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	feed := ical.Event{
+		UID:         fmt.Sprintf("event-%s@%s", event.Key, uidDomain),
+		Stamp:       stamp,
+		Summary:     summary,
+		Description: text,
+		Start:       event.Start.In(zone),
+		End:         event.End.In(zone),
+	}
+	if len(event.Locations) > 0 {
+		describeLocation(&feed, &event.Locations[0], language)
+	}
+	return feed
+}
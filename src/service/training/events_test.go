@@ -0,0 +1,63 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func TestValidateEvent(t *testing.T) {
+	start := time.Date(2024, 6, 8, 10, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1).Add(8 * time.Hour)
+	deadline := start.AddDate(0, 0, -14)
+	late := end.Add(time.Hour)
+	tests := []struct {
+		name    string
+		event   domain.Event
+		wantErr bool
+	}{
+		{"weekend", domain.Event{Start: start, End: end, RegistrationDeadline: &deadline, ParticipantLimit: 80, Price: 15, Currency: "EUR"}, false},
+		{"without dates", domain.Event{}, true},
+		{"ending before start", domain.Event{Start: end, End: start}, true},
+		{"late deadline", domain.Event{Start: start, End: end, RegistrationDeadline: &late}, true},
+		{"negative limit", domain.Event{Start: start, End: end, ParticipantLimit: -1}, true},
+		{"negative price", domain.Event{Start: start, End: end, Price: -5}, true},
+		{"invalid currency", domain.Event{Start: start, End: end, Price: 15, Currency: "Euro"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateEvent(&tt.event); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_eventEntry(t *testing.T) {
+	event := domain.EventDTO{
+		Event: domain.Event{
+			Entity:       domain.Key("jam"),
+			Type:         "parkour-jam",
+			Descriptions: domain.Descriptions{"en": {Title: "Berlin Jam"}},
+			Start:        time.Date(2024, 6, 8, 8, 0, 0, 0, time.UTC),
+			End:          time.Date(2024, 6, 9, 16, 0, 0, 0, time.UTC),
+		},
+		Locations: []domain.Location{{Entity: domain.Key("berlin"), Timezone: "Europe/Berlin"}},
+	}
+	entry := eventEntry(event, "en")
+	if want := time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC); !entry.Date.Equal(want) {
+		t.Errorf("eventEntry() date = %v, want %v", entry.Date, want)
+	}
+	if entry.Begin != 10*3600 || entry.Duration != 32*3600 {
+		t.Errorf("eventEntry() begin = %d, duration = %d, want the local start and both days", entry.Begin, entry.Duration)
+	}
+	if entry.Training != (domain.TrainingSummary{Key: "jam", Type: "parkour-jam", Title: "Berlin Jam", Event: true}) {
+		t.Errorf("eventEntry() summary = %+v", entry.Training)
+	}
+	if entry.Location == nil || entry.Location.Key != "berlin" {
+		t.Errorf("eventEntry() location = %+v, want the first location", entry.Location)
+	}
+	if feed := eventFeed(event, "en"); feed.Start.Location().String() != "Europe/Berlin" || feed.Summary != "Berlin Jam" {
+		t.Errorf("eventFeed() = %+v, want the title in the time zone of the location", feed)
+	}
+}
@@ -0,0 +1,148 @@
+package training
+
+import (
+	"context"
+	"log"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// CancelOccurrence cancels the occurrence of the training on the given date, or all occurrences on that day if the edit
+// does not select one by its beginning. The reason is shown if no occurrence is left on that day.
+func (s *Service) CancelOccurrence(training domain.TrainingDTO, date time.Time, edit domain.OccurrenceEdit, ctx context.Context) error {
+	exceptions, err := cancelOccurrence(training.Training, date, edit)
+	if err != nil {
+		return err
+	}
+	return s.saveExceptions(training.Training, exceptions, ctx)
+}
+
+// MoveOccurrence moves the occurrence of the training on the given date to another date, time or location
+func (s *Service) MoveOccurrence(training domain.TrainingDTO, date time.Time, edit domain.OccurrenceEdit, ctx context.Context) error {
+	exceptions, err := moveOccurrence(training.Training, date, edit)
+	if err != nil {
+		return err
+	}
+	return s.saveExceptions(training.Training, exceptions, ctx)
+}
+
+// AddOccurrence adds an extra occurrence to the training, keeping the other occurrences on that day
+func (s *Service) AddOccurrence(training domain.TrainingDTO, occurrence domain.Exception, ctx context.Context) error {
+	exceptions, err := addOccurrence(training.Training, occurrence)
+	if err != nil {
+		return err
+	}
+	return s.saveExceptions(training.Training, exceptions, ctx)
+}
+
+func (s *Service) saveExceptions(training domain.Training, exceptions []domain.Exception, ctx context.Context) error {
+	training.Exceptions = exceptions
+	if err := Validate(&training); err != nil {
+		return err
+	}
+	if err := s.db.UpdateTrainingExceptions(training.Key, exceptions, ctx); err != nil {
+		return t.Errorf("saving exceptions failed: %w", err)
+	}
+	if err := s.materialise(training.Key, ctx); err != nil {
+		log.Printf("storing occurrences of training %s failed: %v", training.Key, err)
+	}
+	return nil
+}
+
+func cancelOccurrence(training domain.Training, date time.Time, edit domain.OccurrenceEdit) ([]domain.Exception, error) {
+	var rest []domain.Occurrence
+	if edit.Begin != nil {
+		var err error
+		if _, rest, err = takeOccurrence(training, date, edit.Begin); err != nil {
+			return nil, err
+		}
+	} else if len(calendar.Day(training.Cycles, training.Exceptions, date)) == 0 {
+		return nil, t.Errorf("training %s does not take place on %s", training.Key, date.Format(time.DateOnly))
+	}
+	return calendar.ReplaceDay(training.Exceptions, date, rest, edit.Reason), nil
+}
+
+func moveOccurrence(training domain.Training, date time.Time, edit domain.OccurrenceEdit) ([]domain.Exception, error) {
+	if edit.To == nil {
+		return nil, t.Errorf("the new date, time or location is missing")
+	}
+	occurrence, rest, err := takeOccurrence(training, date, edit.Begin)
+	if err != nil {
+		return nil, err
+	}
+	target := occurrence
+	if !edit.To.Date.IsZero() {
+		target.Date = civilDate(edit.To.Date)
+	}
+	if edit.To.Begin != 0 || edit.To.Duration != 0 {
+		target.Begin = edit.To.Begin
+	}
+	if edit.To.Duration != 0 {
+		target.Duration = edit.To.Duration
+	}
+	if edit.To.LocationId != "" {
+		target.LocationId = edit.To.LocationId
+	}
+	target.Reason = edit.Reason
+	if target.Date.Equal(occurrence.Date) {
+		return placeOccurrence(training.Exceptions, target, rest)
+	}
+	exceptions := calendar.ReplaceDay(training.Exceptions, date, rest, edit.Reason)
+	return placeOccurrence(exceptions, target, calendar.Day(training.Cycles, exceptions, target.Date))
+}
+
+func addOccurrence(training domain.Training, occurrence domain.Exception) ([]domain.Exception, error) {
+	if err := calendar.ValidateException(occurrence); err != nil {
+		return nil, err
+	}
+	if occurrence.Duration == 0 {
+		return nil, t.Errorf("the duration is missing")
+	}
+	date := civilDate(occurrence.Date)
+	others := calendar.Day(training.Cycles, training.Exceptions, date)
+	return placeOccurrence(training.Exceptions, domain.Occurrence{
+		Date:       date,
+		Begin:      occurrence.Begin,
+		Duration:   occurrence.Duration,
+		LocationId: occurrence.LocationId,
+		Reason:     occurrence.Reason,
+	}, others)
+}
+
+// placeOccurrence puts the occurrence on its day next to the others, unless it would overlap with one of them
+func placeOccurrence(exceptions []domain.Exception, occurrence domain.Occurrence, others []domain.Occurrence) ([]domain.Exception, error) {
+	day := append(append([]domain.Occurrence{}, others...), occurrence)
+	if first, second, ok := calendar.Overlap(day); ok {
+		return nil, t.Errorf("the occurrence at %s would overlap with the one at %s on %s", calendar.Clock(second.Begin), calendar.Clock(first.Begin), occurrence.Date.Format(time.DateOnly))
+	}
+	calendar.SortOccurrences(day)
+	return calendar.ReplaceDay(exceptions, occurrence.Date, day, ""), nil
+}
+
+// takeOccurrence finds the occurrence on the given date beginning at begin, or the only one on that day if begin is nil,
+// and returns it along with the other occurrences on that day
+func takeOccurrence(training domain.Training, date time.Time, begin *int) (domain.Occurrence, []domain.Occurrence, error) {
+	day := calendar.Day(training.Cycles, training.Exceptions, date)
+	if len(day) == 0 {
+		return domain.Occurrence{}, nil, t.Errorf("training %s does not take place on %s", training.Key, date.Format(time.DateOnly))
+	}
+	if begin == nil {
+		if len(day) > 1 {
+			return domain.Occurrence{}, nil, t.Errorf("training %s takes place several times on %s, please select one by its beginning", training.Key, date.Format(time.DateOnly))
+		}
+		return day[0], nil, nil
+	}
+	for i, occurrence := range day {
+		if occurrence.Begin == *begin {
+			rest := append(append([]domain.Occurrence{}, day[:i]...), day[i+1:]...)
+			return occurrence, rest, nil
+		}
+	}
+	return domain.Occurrence{}, nil, t.Errorf("training %s does not take place at %s on %s", training.Key, calendar.Clock(*begin), date.Format(time.DateOnly))
+}
+
+func civilDate(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,86 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"testing"
+	"time"
+)
+
+func TestOccurrenceEdits(t *testing.T) {
+	friday := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+	evening := 18 * 3600
+	training := domain.Training{
+		Entity: domain.Entity{Key: "1"},
+		Cycles: []domain.Cycle{
+			{Weekday: 5, Begin: evening, Duration: 7200},
+			{Weekday: 5, Begin: 10 * 3600, Duration: 3600},
+		},
+	}
+
+	exceptions, err := cancelOccurrence(training, friday, domain.OccurrenceEdit{Begin: &evening, Reason: "Halle gesperrt"})
+	if err != nil {
+		t.Fatalf("cancelOccurrence() error = %v", err)
+	}
+	if day := calendar.Day(training.Cycles, exceptions, friday); len(day) != 1 || day[0].Begin != 10*3600 {
+		t.Errorf("cancelOccurrence() left %+v, want the morning occurrence", day)
+	}
+	exceptions, err = cancelOccurrence(training, friday, domain.OccurrenceEdit{Reason: "Ostern"})
+	if err != nil {
+		t.Fatalf("cancelOccurrence() error = %v", err)
+	}
+	if len(exceptions) != 1 || exceptions[0].Duration != 0 || exceptions[0].Reason != "Ostern" {
+		t.Errorf("cancelOccurrence() = %+v, want the whole day cancelled", exceptions)
+	}
+	if _, err := cancelOccurrence(training, friday.AddDate(0, 0, 1), domain.OccurrenceEdit{}); err == nil {
+		t.Errorf("cancelOccurrence() cancelled a day without occurrences")
+	}
+
+	saturday := friday.AddDate(0, 0, 1)
+	exceptions, err = moveOccurrence(training, friday, domain.OccurrenceEdit{Begin: &evening, Reason: "verschoben", To: &domain.Exception{Date: saturday, LocationId: "location/2"}})
+	if err != nil {
+		t.Fatalf("moveOccurrence() error = %v", err)
+	}
+	moved := calendar.Day(training.Cycles, exceptions, saturday)
+	if len(moved) != 1 || moved[0].Begin != evening || moved[0].Duration != 7200 || moved[0].LocationId != "location/2" || moved[0].Reason != "verschoben" {
+		t.Errorf("moveOccurrence() = %+v, want the evening occurrence at location 2 on Saturday", moved)
+	}
+	if day := calendar.Day(training.Cycles, exceptions, friday); len(day) != 1 {
+		t.Errorf("moveOccurrence() left %+v on Friday, want one occurrence", day)
+	}
+	if _, err := moveOccurrence(training, friday, domain.OccurrenceEdit{Begin: &evening, To: &domain.Exception{Begin: 10*3600 + 1800, Duration: 3600}}); err == nil {
+		t.Errorf("moveOccurrence() accepted an overlap with the morning occurrence")
+	}
+	if _, err := moveOccurrence(training, friday, domain.OccurrenceEdit{To: &domain.Exception{Date: saturday}}); err == nil {
+		t.Errorf("moveOccurrence() accepted an ambiguous occurrence")
+	}
+
+	exceptions, err = addOccurrence(training, domain.Exception{Date: friday, Begin: 14 * 3600, Duration: 3600})
+	if err != nil {
+		t.Fatalf("addOccurrence() error = %v", err)
+	}
+	if day := calendar.Day(training.Cycles, exceptions, friday); len(day) != 3 {
+		t.Errorf("addOccurrence() = %+v, want three occurrences", day)
+	}
+	if _, err := addOccurrence(training, domain.Exception{Date: friday, Begin: evening, Duration: 3600}); err == nil {
+		t.Errorf("addOccurrence() accepted a duplicate")
+	}
+}
+
+func Test_droppedExceptions(t *testing.T) {
+	monday := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	berlin := time.FixedZone("CEST", 2*3600)
+	stored := []domain.Exception{
+		{Date: time.Date(2024, 4, 1, 0, 0, 0, 0, berlin), Reason: "Ostermontag"},
+		{Date: monday.AddDate(0, 0, 7), Reason: "Halle gesperrt"},
+		{Date: monday.AddDate(0, 0, 14), Begin: 3600, Duration: 3600},
+	}
+	imported := []domain.Exception{
+		{Date: monday},
+		{Date: monday.AddDate(0, 0, 14), Begin: 7200, Duration: 3600},
+	}
+	dropped := droppedExceptions(stored, imported)
+	if len(dropped) != 2 || dropped[0] != stored[1] || dropped[1] != stored[2] {
+		t.Errorf("droppedExceptions() = %+v, want the closed hall and the moved occurrence", dropped)
+	}
+}
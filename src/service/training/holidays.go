@@ -0,0 +1,52 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/holiday"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// Holidays returns the public and school holidays of a state between from (inclusive) and to (exclusive)
+func (s *Service) Holidays(state string, from, to time.Time) ([]domain.Holiday, error) {
+	if err := checkTimespan(from, to); err != nil {
+		return nil, err
+	}
+	code, ok := holiday.State(state)
+	if !ok {
+		return nil, t.Errorf("unknown state %s", state)
+	}
+	school, err := holiday.School()
+	if err != nil {
+		return nil, err
+	}
+	holidays := holiday.Between(domain.HolidayPause{State: code, Public: true, School: true}, school, from, to)
+	if holidays == nil {
+		holidays = []domain.Holiday{}
+	}
+	return holidays, nil
+}
+
+// withHolidays returns the exceptions of a training along with cancellations on the holidays between from (inclusive)
+// and to (exclusive) the training pauses during. Days that have an exception anyway are left as they are, compared by
+// their calendar date whatever location the exception has been stored in.
+func withHolidays(training domain.Training, from, to time.Time) ([]domain.Exception, error) {
+	if training.Holidays == nil {
+		return training.Exceptions, nil
+	}
+	school, err := holiday.School()
+	if err != nil {
+		return nil, err
+	}
+	excepted := make(map[time.Time]bool)
+	for _, exception := range training.Exceptions {
+		excepted[civilDate(exception.Date)] = true
+	}
+	result := append([]domain.Exception{}, training.Exceptions...)
+	for _, h := range holiday.Between(*training.Holidays, school, from, to) {
+		if !excepted[civilDate(h.Date)] {
+			result = append(result, domain.Exception{Date: h.Date, Reason: h.Name})
+		}
+	}
+	return result, nil
+}
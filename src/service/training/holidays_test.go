@@ -0,0 +1,34 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func TestWithHolidays(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data missing: %v", err)
+	}
+	// stored at local midnight, which is still the 24th in UTC
+	christmas := domain.Exception{Date: time.Date(2024, 12, 25, 0, 0, 0, 0, berlin), Begin: 10 * 3600, Duration: 3600, Reason: "Weihnachtstraining"}
+	training := domain.Training{
+		Exceptions: []domain.Exception{christmas},
+		Holidays:   &domain.HolidayPause{State: "NW", Public: true},
+	}
+
+	exceptions, err := withHolidays(training, time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("withHolidays() error = %v", err)
+	}
+	if len(exceptions) != 2 {
+		t.Fatalf("withHolidays() = %+v, want the exception and a cancellation on the 26th", exceptions)
+	}
+	if exceptions[0] != christmas {
+		t.Errorf("withHolidays()[0] = %+v, want %+v", exceptions[0], christmas)
+	}
+	if day := exceptions[1].Date; !day.Equal(time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)) || exceptions[1].Duration != 0 {
+		t.Errorf("withHolidays()[1] = %+v, want a cancellation on 2024-12-26", exceptions[1])
+	}
+}
@@ -0,0 +1,165 @@
+package training
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/dpv"
+	"pkv/api/src/repository/ical"
+	"strings"
+	"time"
+)
+
+// Calendar turns trainings and events into an iCalendar feed. Every cycle becomes a recurring event without the dates
+// that have exceptions, and every exception that does not cancel a date becomes an event of its own, as does every
+// event. A feed of a single training or event is named after it unless a name is given.
+func (s *Service) Calendar(name string, trainings []domain.TrainingDTO, events []domain.EventDTO, language string, ctx context.Context) (ical.Calendar, error) {
+	feed := ical.Calendar{Name: name}
+	resolver := s.newLocationResolver()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	var err error
+	if feed.Name == "" && len(trainings) == 1 && len(events) == 0 {
+		feed.Name, _ = describe(trainings[0].Descriptions, language)
+	}
+	if feed.Name == "" && len(trainings) == 0 && len(events) == 1 {
+		feed.Name, _ = describe(events[0].Descriptions, language)
+	}
+	for _, training := range trainings {
+		summary, text := describe(training.Descriptions, language)
+		stamp := training.Modified
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		zone := Zone(training)
+		// holidays become exceptions, which are only listed for the near future
+		if training.Exceptions, err = withHolidays(training.Training, today.AddDate(-1, 0, 0), today.AddDate(2, 0, 0)); err != nil {
+			return feed, err
+		}
+		overridden := make([]bool, len(training.Exceptions))
+		for n, cycle := range training.Cycles {
+			rrule, err := ical.RRule(cycle, zone)
+			if err != nil {
+				log.Printf("skipping cycle %d of training %s: %v", n, training.Key, err)
+				continue
+			}
+			anchor := cycle.Startdate
+			if anchor.IsZero() {
+				anchor = today
+			}
+			first, ok := calendar.FirstDay(cycle, anchor)
+			if !ok {
+				continue
+			}
+			location, err := resolver.resolve(cycle.LocationId, training.Location, ctx)
+			if err != nil {
+				return feed, err
+			}
+			event := ical.Event{
+				UID:         fmt.Sprintf("training-%s-cycle-%d@%s", training.Key, n, uidDomain),
+				Stamp:       stamp,
+				Summary:     summary,
+				Description: text,
+				RRule:       rrule,
+			}
+			setTime(&event, first, cycle.Begin, cycle.Duration, zone)
+			describeLocation(&event, location, language)
+			var overrides []ical.Event
+			for i, exception := range training.Exceptions {
+				if len(calendar.ComputeDays(cycle, exception.Date, exception.Date.AddDate(0, 0, 1))) == 0 {
+					continue
+				}
+				recurrenceId := calendar.Instant(exception.Date, cycle.Begin, zone)
+				if exception.Duration == 0 || overridden[i] {
+					event.ExDates = append(event.ExDates, recurrenceId)
+					continue
+				}
+				// the first cycle taking place on that day gets moved, all others are cancelled
+				overridden[i] = true
+				override, err := exceptionEvent(event, exception, zone, resolver, training.Location, language, ctx)
+				if err != nil {
+					return feed, err
+				}
+				override.RecurrenceId = recurrenceId
+				overrides = append(overrides, override)
+			}
+			feed.Events = append(feed.Events, event)
+			feed.Events = append(feed.Events, overrides...)
+		}
+		for i, exception := range training.Exceptions {
+			if exception.Duration == 0 || overridden[i] {
+				continue
+			}
+			event := ical.Event{
+				UID:         fmt.Sprintf("training-%s-%s-%d@%s", training.Key, exception.Date.Format(ical.DateFormat), exception.Begin, uidDomain),
+				Stamp:       stamp,
+				Summary:     summary,
+				Description: text,
+			}
+			event, err := exceptionEvent(event, exception, zone, resolver, training.Location, language, ctx)
+			if err != nil {
+				return feed, err
+			}
+			feed.Events = append(feed.Events, event)
+		}
+	}
+	for _, event := range events {
+		feed.Events = append(feed.Events, eventFeed(event, language))
+	}
+	return feed, nil
+}
+
+// exceptionEvent derives an event taking place at the time and location of the exception
+func exceptionEvent(event ical.Event, exception domain.Exception, zone *time.Location, resolver *locationResolver, fallback *domain.Location, language string, ctx context.Context) (ical.Event, error) {
+	location, err := resolver.resolve(exception.LocationId, fallback, ctx)
+	if err != nil {
+		return event, err
+	}
+	event.RRule = ""
+	event.ExDates = nil
+	setTime(&event, exception.Date, exception.Begin, exception.Duration, zone)
+	event.Location, event.Lat, event.Lng = "", 0, 0
+	describeLocation(&event, location, language)
+	return event, nil
+}
+
+// uidDomain makes the UIDs of calendar events globally unique
+const uidDomain = "parkour-deutschland.de"
+
+// setTime sets start and end of an event to the wall clock times in the zone
+func setTime(event *ical.Event, date time.Time, begin, duration int, zone *time.Location) {
+	event.Start = calendar.Instant(date, begin, zone)
+	event.End = event.Start.Add(time.Duration(duration) * time.Second)
+}
+
+// describe returns title and text of the description in the given language, or else the first configured language
+// present, see domain.Descriptions.Negotiate, so that feeds do not change between requests
+func describe(descriptions domain.Descriptions, language string) (string, string) {
+	var fallback []string
+	if dpv.ConfigInstance != nil {
+		for _, l := range dpv.ConfigInstance.Settings.Languages {
+			fallback = append(fallback, l.Key)
+		}
+	}
+	if language, ok := descriptions.Negotiate([]string{language}, fallback); ok {
+		return descriptions[language].Title, descriptions[language].Text
+	}
+	return "", ""
+}
+
+func describeLocation(event *ical.Event, location *domain.Location, language string) {
+	if location == nil {
+		return
+	}
+	title, _ := describe(location.Descriptions, language)
+	var parts []string
+	for _, part := range []string{title, location.City} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	event.Location = strings.Join(parts, ", ")
+	event.Lat = location.Lat
+	event.Lng = location.Lng
+}
@@ -0,0 +1,25 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+)
+
+func Test_describe(t *testing.T) {
+	descriptions := domain.Descriptions{
+		"fr": {Title: "Bonjour"},
+		"es": {Title: "Hola"},
+		"it": {Title: "Ciao"},
+	}
+	for range 20 {
+		if title, _ := describe(descriptions, "de"); title != "Hola" {
+			t.Fatalf("describe() = %q, want the lowest language key es", title)
+		}
+	}
+	if title, _ := describe(descriptions, "fr"); title != "Bonjour" {
+		t.Errorf("describe() = %q, want the requested language fr", title)
+	}
+	if title, text := describe(nil, "de"); title != "" || text != "" {
+		t.Errorf("describe() = %q, %q, want nothing", title, text)
+	}
+}
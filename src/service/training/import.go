@@ -0,0 +1,169 @@
+package training
+
+import (
+	"context"
+	"log"
+	"pkv/api/src/api"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/ical"
+	"pkv/api/src/repository/t"
+	"pkv/api/src/service/description"
+	"slices"
+	"time"
+)
+
+// ImportSource is stored as importedFrom in the information of imported trainings
+const ImportSource = "ical"
+
+// ImportCalendar turns every recurring event of an iCalendar object into a training organised by the given user.
+// Events that have been imported by the same organiser before are updated, moved to the location if one is given. The
+// calendar is the source of truth for their exceptions, stored exceptions it does not contain anymore are listed in the
+// report. Events the cycle model cannot express are rejected and listed in the report along with the reason in the
+// message language, as are conflicts with other trainings, which reject events only if the write options of the
+// context say so.
+func (s *Service) ImportCalendar(data []byte, organiserKey string, locationKey string, language string, messageLanguage string, ctx context.Context) (domain.ImportReport, error) {
+	report := domain.ImportReport{Events: []domain.ImportedEvent{}}
+	root, err := ical.Parse(data)
+	if err != nil {
+		return report, t.Errorf("parsing calendar failed: %w", err)
+	}
+	organiser, err := s.db.Users.Read(organiserKey, ctx)
+	if err != nil {
+		return report, t.Errorf("reading organiser failed: %w", err)
+	}
+	var location *domain.Location
+	if locationKey != "" {
+		if location, err = s.db.Locations.Read(locationKey, ctx); err != nil {
+			return report, t.Errorf("reading location failed: %w", err)
+		}
+	}
+	zone := calendar.Zone()
+	if location != nil {
+		zone = calendar.Zone(location.Timezone)
+	}
+	recurrences, rejections := ical.Recurrences(root, zone)
+	for _, recurrence := range recurrences {
+		event := domain.ImportedEvent{UID: recurrence.UID, Summary: recurrence.Summary}
+		options := &api.WriteOptions{RejectConflicts: rejectsConflicts(ctx)}
+		training, created, dropped, err := s.importRecurrence(recurrence, organiser, location, language, api.WithWriteOptions(ctx, options))
+		event.Warnings = api.LocaliseAll(options.Warnings, messageLanguage)
+		if err == nil {
+			// the training has been written, so its occurrences are stored and its translations queued as by the hooks
+			// of the CRUD endpoints
+			if err := s.materialise(training.Key, ctx); err != nil {
+				log.Printf("storing occurrences of imported training %s failed: %v", training.Key, err)
+			}
+			if err := s.translations.Enqueue("trainings", training.Key, training.Descriptions, ctx); err != nil {
+				log.Printf("queueing translations of imported training %s failed: %v", training.Key, err)
+			}
+		}
+		switch {
+		case err != nil:
+			event.Status = "rejected"
+			event.Error = t.Localise(err, messageLanguage)
+		case created:
+			event.Status = "created"
+			event.Key = training.Key
+		default:
+			event.Status = "updated"
+			event.Key = training.Key
+			event.DroppedExceptions = dropped
+		}
+		report.Events = append(report.Events, event)
+	}
+	for _, rejection := range rejections {
+		report.Events = append(report.Events, domain.ImportedEvent{
+			UID:     rejection.UID,
+			Summary: rejection.Summary,
+			Status:  "rejected",
+			Error:   t.Localise(rejection.Err, messageLanguage),
+		})
+	}
+	return report, nil
+}
+
+// importRecurrence creates a training for the recurrence or updates the one imported before, returning the exceptions
+// of the latter the recurrence does not contain anymore
+func (s *Service) importRecurrence(recurrence ical.Recurrence, organiser *domain.User, location *domain.Location, language string, ctx context.Context) (*domain.Training, bool, []domain.Exception, error) {
+	training, err := s.db.GetImportedTraining(ImportSource, recurrence.UID, organiser.Key, ctx)
+	if err != nil {
+		return nil, false, nil, t.Errorf("checking for existing trainings failed: %w", err)
+	}
+	now := time.Now().UTC()
+	created := training == nil
+	if created {
+		training = &domain.Training{
+			Entity:       domain.Entity{Created: now},
+			Type:         "parkour-training",
+			Information:  map[string]string{},
+			Descriptions: domain.Descriptions{},
+		}
+	}
+	if training.Information == nil {
+		training.Information = map[string]string{}
+	}
+	if training.Descriptions == nil {
+		training.Descriptions = domain.Descriptions{}
+	}
+	training.Modified = now
+	training.Information["importedFrom"] = ImportSource
+	training.Information["importedId"] = recurrence.UID
+	training.Information["importedLocation"] = recurrence.Location
+	training.Descriptions[language] = domain.Description{
+		Title: recurrence.Summary,
+		Text:  recurrence.Description,
+	}
+	description.RenderAll(training.Descriptions, s.db, ctx)
+	training.Cycles = []domain.Cycle{recurrence.Cycle}
+	dropped := droppedExceptions(training.Exceptions, recurrence.Exceptions)
+	training.Exceptions = recurrence.Exceptions
+	if err := Validate(training); err != nil {
+		return nil, false, nil, err
+	}
+	locationKey := ""
+	if location != nil {
+		locationKey = location.Key
+	}
+	if err := s.checkConflicts(training, locationKey, ctx); err != nil {
+		return nil, false, nil, err
+	}
+	if !created {
+		if err := s.db.Trainings.Replace(training, ctx); err != nil {
+			return nil, false, nil, t.Errorf("updating training failed: %w", err)
+		}
+		if location != nil {
+			if err := s.db.MoveTrainingToLocation(training.Key, location.Key, ctx); err != nil {
+				return nil, false, nil, err
+			}
+		}
+		return training, false, dropped, nil
+	}
+	if err := s.db.Trainings.Create(training, ctx); err != nil {
+		return nil, false, nil, t.Errorf("creating training failed: %w", err)
+	}
+	if err := s.db.UserOrganisesTraining(*organiser, *training, ctx); err != nil {
+		return nil, false, nil, err
+	}
+	if location != nil {
+		if err := s.db.TrainingHappensAtLocation(training, location, ctx); err != nil {
+			return nil, false, nil, err
+		}
+	}
+	return training, true, nil, nil
+}
+
+// droppedExceptions returns the stored exceptions that are not among the imported ones, e.g. cancellations entered
+// through the API since the last import
+func droppedExceptions(stored, imported []domain.Exception) []domain.Exception {
+	var dropped []domain.Exception
+	for _, exception := range stored {
+		if !slices.ContainsFunc(imported, func(i domain.Exception) bool {
+			return civilDate(i.Date).Equal(civilDate(exception.Date)) && i.Begin == exception.Begin &&
+				i.Duration == exception.Duration && i.LocationId == exception.LocationId
+		}) {
+			dropped = append(dropped, exception)
+		}
+	}
+	return dropped
+}
@@ -0,0 +1,103 @@
+package training
+
+import (
+	"context"
+	"log"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/t"
+	"time"
+)
+
+// MaterialisedDays is the number of days from today for which the occurrences of all trainings are stored
+const MaterialisedDays = 365
+
+// RefreshOccurrences stores the occurrences of all trainings for the next MaterialisedDays days and removes the ones
+// that have passed
+func (s *Service) RefreshOccurrences(ctx context.Context) error {
+	from, to := materialisedWindow(time.Now())
+	trainings, err := s.FilterTrainings(domain.TrainingQueryOptions{}, ctx)
+	if err != nil {
+		return err
+	}
+	for _, training := range trainings {
+		if err := s.storeOccurrences(training, from, to, ctx); err != nil {
+			return err
+		}
+	}
+	if err := s.db.RemoveStoredOccurrences(from, ctx); err != nil {
+		return err
+	}
+	return s.db.SetOccurrenceWindow(domain.OccurrenceWindow{From: from, To: to}, ctx)
+}
+
+// KeepOccurrencesFresh refreshes the stored occurrences right away and then in the given interval
+func (s *Service) KeepOccurrencesFresh(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RefreshOccurrences(context.Background()); err != nil {
+			log.Printf("periodic refreshing of occurrences failed: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+// MaterialiseHook stores the occurrences of a training after it has been written through the generic CRUD endpoints,
+// or removes them if it has been deleted
+func (s *Service) MaterialiseHook(training *domain.Training, ctx context.Context) error {
+	return s.materialise(training.Key, ctx)
+}
+
+func (s *Service) materialise(key string, ctx context.Context) error {
+	exists, err := s.db.Trainings.Has(key, ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return s.db.ReplaceStoredOccurrences(key, nil, ctx)
+	}
+	training, err := s.ReadTraining(key, ctx)
+	if err != nil {
+		return err
+	}
+	from, to := materialisedWindow(time.Now())
+	return s.storeOccurrences(training, from, to, ctx)
+}
+
+func (s *Service) storeOccurrences(training domain.TrainingDTO, from, to time.Time, ctx context.Context) error {
+	occurrences, err := storedOccurrences(training, from, to)
+	if err != nil {
+		return err
+	}
+	if err := s.db.ReplaceStoredOccurrences(training.Key, occurrences, ctx); err != nil {
+		return t.Errorf("storing occurrences failed: %w", err)
+	}
+	return nil
+}
+
+// storedOccurrences computes the occurrences of the training on the days between from (inclusive) and to (exclusive)
+// to be stored, along with the key of the location each one takes place at
+func storedOccurrences(training domain.TrainingDTO, from, to time.Time) ([]domain.StoredOccurrence, error) {
+	exceptions, err := withHolidays(training.Training, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// only the window is stored, occurrences that have passed are pruned by RefreshOccurrences
+	occurrences := calendar.TrimOccurrences(calendar.ComputeOccurrences(training.Cycles, exceptions, from, to, Zone(training)), from, to.Add(-time.Nanosecond))
+	result := make([]domain.StoredOccurrence, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		location := LocationKey(occurrence.LocationId)
+		if location == "" {
+			location = training.LocationKey
+		}
+		result = append(result, domain.StoredOccurrence{Occurrence: occurrence, TrainingKey: training.Key, LocationKey: location})
+	}
+	return result, nil
+}
+
+// materialisedWindow returns the days for which occurrences are stored, starting today
+func materialisedWindow(now time.Time) (time.Time, time.Time) {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 0, MaterialisedDays)
+}
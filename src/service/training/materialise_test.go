@@ -0,0 +1,37 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func Test_storedOccurrences(t *testing.T) {
+	from, to := materialisedWindow(time.Date(2024, 4, 3, 15, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC); !from.Equal(want) || !to.Equal(want.AddDate(0, 0, MaterialisedDays)) {
+		t.Errorf("materialisedWindow() = %v, %v", from, to)
+	}
+	training := domain.TrainingDTO{
+		Training: domain.Training{
+			Entity: domain.Key("1"),
+			Cycles: []domain.Cycle{{Weekday: 5, Begin: 18 * 3600, Duration: 7200}},
+			Exceptions: []domain.Exception{
+				{Date: time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC), Begin: 18 * 3600, Duration: 7200, LocationId: "location/2"},
+			},
+		},
+		LocationKey: "1",
+	}
+	occurrences, err := storedOccurrences(training, from, from.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatalf("storedOccurrences() error = %v", err)
+	}
+	if len(occurrences) != 2 {
+		t.Fatalf("storedOccurrences() = %+v, want 2 occurrences", occurrences)
+	}
+	if got := occurrences[0]; got.TrainingKey != "1" || got.LocationKey != "1" || got.LocationId != "" {
+		t.Errorf("storedOccurrences() first = %+v, want the location of the training", got)
+	}
+	if got := occurrences[1]; got.LocationKey != "2" || got.LocationId != "location/2" {
+		t.Errorf("storedOccurrences() second = %+v, want the location of the exception", got)
+	}
+}
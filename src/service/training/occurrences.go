@@ -0,0 +1,120 @@
+package training
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/t"
+	"strings"
+	"time"
+)
+
+// MaxOccurrenceDays limits the time span that is expanded into occurrences at once
+const MaxOccurrenceDays = 366
+
+// Occurrences computes the occurrences of a training between from (inclusive) and to (exclusive). Cancelled
+// occurrences are left out unless requested, in which case they are marked as such.
+func (s *Service) Occurrences(key string, from, to time.Time, cancelled bool, ctx context.Context) ([]domain.OccurrenceDTO, error) {
+	training, err := s.ReadTraining(key, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.OccurrencesOf(training, from, to, cancelled, ctx)
+}
+
+// OccurrencesOf computes the occurrences of a training that has already been read, see Occurrences
+func (s *Service) OccurrencesOf(training domain.TrainingDTO, from, to time.Time, cancelled bool, ctx context.Context) ([]domain.OccurrenceDTO, error) {
+	if err := checkTimespan(from, to); err != nil {
+		return nil, err
+	}
+	exceptions, err := withHolidays(training.Training, from, to)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := calendar.ComputeOccurrences(training.Cycles, exceptions, from, to, Zone(training))
+	if cancelled {
+		occurrences = append(occurrences, calendar.CancelledOccurrences(training.Cycles, exceptions, from, to, Zone(training))...)
+		calendar.SortOccurrences(occurrences)
+	}
+	result, err := s.resolveLocations(occurrences, training.Location, ctx)
+	if err != nil {
+		return nil, err
+	}
+	coachings, err := s.db.GetCoachings(training.Key, from, to, ctx)
+	if err != nil {
+		return nil, t.Errorf("reading coaches failed: %w", err)
+	}
+	coaches := coachesByDate(coachings)
+	for i := range result {
+		result[i].Coaches = effectiveCoaches(coaches, training.Organisers, result[i].Date)
+	}
+	return result, nil
+}
+
+// Zone returns the time zone of the training, which defaults to the one of its location
+func Zone(training domain.TrainingDTO) *time.Location {
+	if training.Location != nil {
+		return calendar.Zone(training.Timezone, training.Location.Timezone)
+	}
+	return calendar.Zone(training.Timezone)
+}
+
+func checkTimespan(from, to time.Time) error {
+	if !to.After(from) {
+		return t.Errorf("the end of the time span needs to be after its beginning")
+	}
+	if to.Sub(from) > MaxOccurrenceDays*24*time.Hour {
+		return t.Errorf("the time span cannot be longer than %d days", MaxOccurrenceDays)
+	}
+	return nil
+}
+
+// resolveLocations looks up the location of each occurrence, falling back to the location of the training
+func (s *Service) resolveLocations(occurrences []domain.Occurrence, fallback *domain.Location, ctx context.Context) ([]domain.OccurrenceDTO, error) {
+	resolver := s.newLocationResolver()
+	result := make([]domain.OccurrenceDTO, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		location, err := resolver.resolve(occurrence.LocationId, fallback, ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, domain.OccurrenceDTO{Occurrence: occurrence, Location: location})
+	}
+	return result, nil
+}
+
+// locationResolver reads locations referenced by cycles and exceptions, reading each location only once
+type locationResolver struct {
+	s         *Service
+	locations map[string]*domain.Location
+}
+
+func (s *Service) newLocationResolver() *locationResolver {
+	return &locationResolver{s, make(map[string]*domain.Location)}
+}
+
+func (l *locationResolver) resolve(id string, fallback *domain.Location, ctx context.Context) (*domain.Location, error) {
+	if id == "" {
+		return fallback, nil
+	}
+	key := LocationKey(id)
+	if location, ok := l.locations[key]; ok {
+		return location, nil
+	}
+	location, err := l.s.db.Locations.Read(key, ctx)
+	if err != nil {
+		return nil, t.Errorf("could not resolve location %s: %w", id, err)
+	}
+	location.Photos = domain.Photos{}
+	location.Comments = nil
+	l.locations[key] = location
+	return location, nil
+}
+
+// LocationKey turns a location id such as "location/123" into the key "123"
+func LocationKey(id string) string {
+	if _, key, found := strings.Cut(id, "/"); found {
+		return key
+	}
+	return id
+}
@@ -0,0 +1,108 @@
+package training
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/t"
+	"strings"
+	"time"
+)
+
+// Register signs the user up for the occurrence of the training on the given date. If the training has a capacity and
+// all places are taken, the user is put on the waitlist. Registering again keeps the place.
+func (s *Service) Register(key string, userKey string, date time.Time, ctx context.Context) (domain.Attendee, error) {
+	training, err := s.ReadTraining(key, ctx)
+	if err != nil {
+		return domain.Attendee{}, err
+	}
+	if err := checkUpcoming(training, date, time.Now()); err != nil {
+		return domain.Attendee{}, err
+	}
+	if _, err := s.db.UserRegistersForTraining(userKey, key, date, ctx); err != nil {
+		return domain.Attendee{}, t.Errorf("registration failed: %w", err)
+	}
+	attendance, err := s.Attendance(training, date, ctx)
+	if err != nil {
+		return domain.Attendee{}, err
+	}
+	for _, attendee := range attendance.Attendees {
+		if attendee.UserKey == userKey {
+			return attendee, nil
+		}
+	}
+	return domain.Attendee{}, t.Errorf("registration of user %s not found", userKey)
+}
+
+// Unregister withdraws the registration of the user for the occurrence of the training on the given date. The first
+// user on the waitlist then takes the place that became free.
+func (s *Service) Unregister(key string, userKey string, date time.Time, ctx context.Context) error {
+	deleted, err := s.db.DeleteRegistration(userKey, key, date, ctx)
+	if err != nil {
+		return t.Errorf("withdrawing registration failed: %w", err)
+	}
+	if !deleted {
+		return t.Errorf("user %s is not registered for training %s on %s", userKey, key, date.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// Attendance lists the users registered for the occurrence of the training on the given date
+func (s *Service) Attendance(training domain.TrainingDTO, date time.Time, ctx context.Context) (domain.Attendance, error) {
+	registrations, err := s.db.GetRegistrations(training.Key, date, ctx)
+	if err != nil {
+		return domain.Attendance{}, t.Errorf("reading registrations failed: %w", err)
+	}
+	return queue(registrations, training.Capacity, date), nil
+}
+
+// queue assigns the places of an occurrence to the registrations in the order they were made. Once the capacity is
+// reached, the remaining registrations are waitlisted. A capacity of 0 means that there is no limit.
+func queue(registrations []domain.RegistrationDTO, capacity int, date time.Time) domain.Attendance {
+	attendance := domain.Attendance{Date: date, Capacity: capacity, Attendees: []domain.Attendee{}}
+	for i, registration := range registrations {
+		attendee := domain.Attendee{
+			UserKey:    strings.TrimPrefix(registration.From, "users/"),
+			Status:     "attending",
+			Registered: registration.Created,
+		}
+		if registration.User != nil {
+			attendee.Name = registration.User.Name
+		}
+		if capacity > 0 && i >= capacity {
+			attendee.Status = "waitlisted"
+			attendee.Position = i - capacity + 1
+			attendance.Waitlisted++
+		} else {
+			attendance.Attending++
+		}
+		attendance.Attendees = append(attendance.Attendees, attendee)
+	}
+	return attendance
+}
+
+// checkUpcoming makes sure the training takes place on the given date and the occurrence is not over yet
+func checkUpcoming(training domain.TrainingDTO, date time.Time, now time.Time) error {
+	occurrences, err := occurrencesOn(training, date)
+	if err != nil {
+		return err
+	}
+	if !occurrences[len(occurrences)-1].End.After(now) {
+		return t.Errorf("the training on %s is already over", date.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// occurrencesOn returns the occurrences of the training on the given date, failing if there are none
+func occurrencesOn(training domain.TrainingDTO, date time.Time) ([]domain.Occurrence, error) {
+	next := date.AddDate(0, 0, 1)
+	exceptions, err := withHolidays(training.Training, date, next)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := calendar.ComputeOccurrences(training.Cycles, exceptions, date, next, Zone(training))
+	if len(occurrences) == 0 {
+		return nil, t.Errorf("training %s does not take place on %s", training.Key, date.Format(time.DateOnly))
+	}
+	return occurrences, nil
+}
@@ -0,0 +1,61 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+	"time"
+)
+
+func Test_queue(t *testing.T) {
+	date := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+	var registrations []domain.RegistrationDTO
+	for i, key := range []string{"anna", "ben", "carla", "dave"} {
+		registrations = append(registrations, domain.RegistrationDTO{
+			Registration: domain.Registration{From: "users/" + key, Created: date.Add(time.Duration(i) * time.Minute)},
+			User:         &domain.User{Name: key},
+		})
+	}
+	attendance := queue(registrations, 2, date)
+	if attendance.Attending != 2 || attendance.Waitlisted != 2 {
+		t.Errorf("queue() attending = %d, waitlisted = %d, want 2 and 2", attendance.Attending, attendance.Waitlisted)
+	}
+	carla := attendance.Attendees[2]
+	if carla.UserKey != "carla" || carla.Name != "carla" || carla.Status != "waitlisted" || carla.Position != 1 {
+		t.Errorf("queue() third attendee = %+v, want carla first on the waitlist", carla)
+	}
+	// once anna withdraws, carla moves up
+	attendance = queue(registrations[1:], 2, date)
+	if got := attendance.Attendees[1]; got.UserKey != "carla" || got.Status != "attending" || got.Position != 0 {
+		t.Errorf("queue() second attendee = %+v, want carla attending", got)
+	}
+	if got := attendance.Attendees[2]; got.Status != "waitlisted" || got.Position != 1 {
+		t.Errorf("queue() third attendee = %+v, want first on the waitlist", got)
+	}
+	attendance = queue(registrations, 0, date)
+	if attendance.Attending != 4 || attendance.Waitlisted != 0 {
+		t.Errorf("queue() without capacity attending = %d, waitlisted = %d, want 4 and 0", attendance.Attending, attendance.Waitlisted)
+	}
+}
+
+func Test_checkUpcoming(t *testing.T) {
+	training := domain.TrainingDTO{Training: domain.Training{
+		Entity: domain.Entity{Key: "1"},
+		Cycles: []domain.Cycle{{
+			Weekday:   5,
+			Begin:     18 * 3600,
+			Duration:  2 * 3600,
+			Startdate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		Timezone: "Europe/Berlin",
+	}}
+	friday := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+	if err := checkUpcoming(training, friday, friday); err != nil {
+		t.Errorf("checkUpcoming() error = %v", err)
+	}
+	if err := checkUpcoming(training, friday.AddDate(0, 0, 1), friday); err == nil {
+		t.Errorf("checkUpcoming() accepted a Saturday")
+	}
+	if err := checkUpcoming(training, friday, friday.Add(20*time.Hour)); err == nil {
+		t.Errorf("checkUpcoming() accepted an occurrence that is over")
+	}
+}
@@ -0,0 +1,58 @@
+package training
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/graph"
+	"pkv/api/src/repository/t"
+	"pkv/api/src/service/translation"
+)
+
+type Service struct {
+	db           *graph.Db
+	translations *translation.Service
+}
+
+func NewService(db *graph.Db, translations *translation.Service) *Service {
+	return &Service{db: db, translations: translations}
+}
+
+// ErrNotFound is wrapped by the error of ReadTraining if there is no training with the key
+var ErrNotFound = t.Errorf("not found")
+
+// ReadTraining reads a training including its cycles, exceptions, organisers and the location it happens at
+func (s *Service) ReadTraining(key string, ctx context.Context) (domain.TrainingDTO, error) {
+	trainings, err := s.db.GetFilteredTrainings(domain.TrainingQueryOptions{
+		Key:     key,
+		Include: includeCalendar(nil),
+	}, ctx)
+	if err != nil {
+		return domain.TrainingDTO{}, t.Errorf("read training failed: %w", err)
+	}
+	if len(trainings) == 0 {
+		return domain.TrainingDTO{}, t.Errorf("training %s %w", key, ErrNotFound)
+	}
+	return trainings[0], nil
+}
+
+// FilterTrainings reads the trainings matching the options including everything needed to compute their occurrences
+func (s *Service) FilterTrainings(options domain.TrainingQueryOptions, ctx context.Context) ([]domain.TrainingDTO, error) {
+	options.Include = includeCalendar(options.Include)
+	trainings, err := s.db.GetFilteredTrainings(options, ctx)
+	if err != nil {
+		return nil, t.Errorf("querying trainings failed: %w", err)
+	}
+	return trainings, nil
+}
+
+// includeCalendar adds the cycles, exceptions, location and organisers to the set of included details
+func includeCalendar(include map[string]struct{}) map[string]struct{} {
+	if include == nil {
+		include = make(map[string]struct{})
+	}
+	include["cycles"] = struct{}{}
+	include["exceptions"] = struct{}{}
+	include["location"] = struct{}{}
+	include["organisers"] = struct{}{}
+	return include
+}
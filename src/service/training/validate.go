@@ -0,0 +1,79 @@
+package training
+
+import (
+	"context"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/calendar"
+	"pkv/api/src/repository/holiday"
+	"pkv/api/src/repository/t"
+)
+
+// Validate returns the first problem found in the cycles and exceptions of a training, if any
+func Validate(training *domain.Training) error {
+	for i, cycle := range training.Cycles {
+		if err := calendar.ValidateCycle(cycle); err != nil {
+			return t.Errorf("cycle %d: %w", i+1, err)
+		}
+	}
+	for i, exception := range training.Exceptions {
+		if err := calendar.ValidateException(exception); err != nil {
+			return t.Errorf("exception %d: %w", i+1, err)
+		}
+	}
+	return validateHolidays(training.Holidays)
+}
+
+// validateHolidays checks that a training pausing during holidays refers to a known state and kind of holidays
+func validateHolidays(pause *domain.HolidayPause) error {
+	if pause == nil {
+		return nil
+	}
+	if _, ok := holiday.State(pause.State); !ok {
+		return t.Errorf("unknown state %s", pause.State)
+	}
+	if !pause.Public && !pause.School {
+		return t.Errorf("pausing during holidays requires public holidays, school holidays or both")
+	}
+	return nil
+}
+
+// ValidateHook calls Validate before trainings are written through the generic CRUD endpoints
+func ValidateHook(training *domain.Training, ctx context.Context) error {
+	return Validate(training)
+}
+
+// ValidateTraining checks every cycle and exception of a training and describes the valid cycles. Problems are
+// described in the given language.
+func ValidateTraining(training domain.Training, language string) domain.TrainingValidation {
+	validation := domain.TrainingValidation{
+		Valid:      true,
+		Cycles:     []domain.CycleValidation{},
+		Exceptions: []domain.ExceptionValidation{},
+	}
+	for _, cycle := range training.Cycles {
+		var result domain.CycleValidation
+		if err := calendar.ValidateCycle(cycle); err != nil {
+			validation.Valid = false
+			result.Error = t.Localise(err, language)
+		} else {
+			result.Schedule = make(map[string]string)
+			for _, language := range calendar.ScheduleLanguages {
+				result.Schedule[language] = calendar.DescribeCycle(cycle, language)
+			}
+		}
+		validation.Cycles = append(validation.Cycles, result)
+	}
+	for _, exception := range training.Exceptions {
+		var result domain.ExceptionValidation
+		if err := calendar.ValidateException(exception); err != nil {
+			validation.Valid = false
+			result.Error = t.Localise(err, language)
+		}
+		validation.Exceptions = append(validation.Exceptions, result)
+	}
+	if err := validateHolidays(training.Holidays); err != nil {
+		validation.Valid = false
+		validation.Holidays = t.Localise(err, language)
+	}
+	return validation
+}
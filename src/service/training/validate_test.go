@@ -0,0 +1,55 @@
+package training
+
+import (
+	"pkv/api/src/domain"
+	"testing"
+)
+
+func TestValidateTraining(t *testing.T) {
+	training := domain.Training{
+		Cycles: []domain.Cycle{
+			{Weekday: 5, Begin: 18 * 3600, Duration: 2 * 3600},
+			{Interval: 14},
+		},
+		Exceptions: []domain.Exception{{}},
+	}
+	validation := ValidateTraining(training, "en")
+	if validation.Valid {
+		t.Errorf("ValidateTraining() is valid, want invalid")
+	}
+	if got := validation.Cycles[0].Schedule["de"]; got != "Jeden Freitag, 18:00–20:00" {
+		t.Errorf("ValidateTraining() schedule = %q, want %q", got, "Jeden Freitag, 18:00–20:00")
+	}
+	if validation.Cycles[1].Error == "" || validation.Cycles[1].Schedule != nil {
+		t.Errorf("ValidateTraining() cycle 2 = %+v, want an error", validation.Cycles[1])
+	}
+	if validation.Exceptions[0].Error == "" {
+		t.Errorf("ValidateTraining() exception 1 = %+v, want an error", validation.Exceptions[0])
+	}
+	if err := Validate(&training); err == nil {
+		t.Errorf("Validate() error = nil, want an error")
+	}
+	training.Cycles = training.Cycles[:1]
+	training.Exceptions = nil
+	if err := Validate(&training); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestValidateHolidays(t *testing.T) {
+	tests := []struct {
+		pause   *domain.HolidayPause
+		wantErr bool
+	}{
+		{nil, false},
+		{&domain.HolidayPause{State: "NRW", Public: true}, false},
+		{&domain.HolidayPause{State: "BY", School: true}, false},
+		{&domain.HolidayPause{State: "XX", Public: true}, true},
+		{&domain.HolidayPause{State: "NW"}, true},
+	}
+	for _, tt := range tests {
+		if err := validateHolidays(tt.pause); (err != nil) != tt.wantErr {
+			t.Errorf("validateHolidays(%+v) error = %v, wantErr %v", tt.pause, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package translation
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/dpv"
+	"pkv/api/src/repository/graph"
+	"pkv/api/src/repository/t"
+	"pkv/api/src/service/description"
+	"time"
+)
+
+type Service struct {
+	db         *graph.Db
+	translator description.Translator
+}
+
+func NewService(db *graph.Db, translator description.Translator) *Service {
+	return &Service{db, translator}
+}
+
+// Hook queues the missing and outdated translations of an entity after it has been written through the generic CRUD
+// endpoints into the given collection
+func Hook[T interface {
+	domain.Described
+	GetKey() string
+}](s *Service, collection string) func(item T, ctx context.Context) error {
+	return func(item T, ctx context.Context) error {
+		return s.Enqueue(collection, item.GetKey(), item.GetDescriptions(), ctx)
+	}
+}
+
+// EditHook marks the descriptions of an entity about to be written through the generic CRUD endpoints as written by
+// a human if they claim to be translated automatically but differ from the stored ones, so that a human correcting an
+// automatic translation is never overwritten by the next translation. It expects descriptions normalised by RenderAll.
+func EditHook[T interface {
+	domain.Described
+	graph.Entity
+}](em graph.EntityManager[T]) func(item T, ctx context.Context) error {
+	return func(item T, ctx context.Context) error {
+		descriptions := item.GetDescriptions()
+		var stored domain.Descriptions
+		if item.GetKey() != "" && claimsTranslation(descriptions) {
+			exists, err := em.Has(item.GetKey(), ctx)
+			if err != nil {
+				return err
+			}
+			if exists {
+				original, err := em.Read(item.GetKey(), ctx)
+				if err != nil {
+					return err
+				}
+				stored = original.GetDescriptions()
+			}
+		}
+		markEdits(descriptions, stored)
+		return nil
+	}
+}
+
+func claimsTranslation(descriptions domain.Descriptions) bool {
+	for _, d := range descriptions {
+		if d.Translated {
+			return true
+		}
+	}
+	return false
+}
+
+// markEdits keeps the descriptions translated automatically only if they equal a stored automatic translation, whose
+// source they keep. Others have been written or corrected by a human.
+func markEdits(descriptions domain.Descriptions, stored domain.Descriptions) {
+	for language, d := range descriptions {
+		if !d.Translated {
+			continue
+		}
+		if s, ok := stored[language]; ok && s.Translated && s.Title == d.Title && s.Text == d.Text {
+			d.Source = s.Source
+		} else {
+			d.Translated, d.Source = false, ""
+		}
+		descriptions[language] = d
+	}
+}
+
+// Enqueue queues a translation of the description written by a human into every configured language that lacks a
+// description or whose automatic translation is outdated. Nothing is queued unless translation is enabled.
+func (s *Service) Enqueue(collection string, key string, descriptions domain.Descriptions, ctx context.Context) error {
+	if !dpv.ConfigInstance.Translation.Enabled {
+		return nil
+	}
+	source, targets := pending(descriptions, languages())
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, target := range targets {
+		if err := s.db.EnqueueTranslation(domain.TranslationJob{
+			Collection: collection,
+			EntityKey:  key,
+			Source:     source,
+			Target:     target,
+			Created:    now,
+			Due:        now,
+		}, ctx); err != nil {
+			return t.Errorf("queueing translations failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Origin identifies the language and content of a description, so that translations made from it can be recognised
+// as outdated once it changes
+func Origin(language string, d domain.Description) string {
+	sum := md5.Sum([]byte(d.Title + "\n" + d.Text))
+	return language + ":" + hex.EncodeToString(sum[:])
+}
+
+// pending returns the first of the languages with a description written by a human, and the other languages lacking
+// a description or having one translated from a different source. Descriptions written by humans are never targets.
+func pending(descriptions domain.Descriptions, languages []string) (string, []string) {
+	source := ""
+	for _, language := range languages {
+		if d, ok := descriptions[language]; ok && !d.Translated && !empty(d) {
+			source = language
+			break
+		}
+	}
+	if source == "" {
+		return "", nil
+	}
+	origin := Origin(source, descriptions[source])
+	var targets []string
+	for _, language := range languages {
+		if language == source {
+			continue
+		}
+		if d := descriptions[language]; empty(d) || d.Translated && d.Source != origin {
+			targets = append(targets, language)
+		}
+	}
+	return source, targets
+}
+
+func empty(d domain.Description) bool {
+	return d.Title == "" && d.Text == ""
+}
+
+func languages() []string {
+	var keys []string
+	for _, language := range dpv.ConfigInstance.Settings.Languages {
+		keys = append(keys, language.Key)
+	}
+	return keys
+}
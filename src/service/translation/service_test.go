@@ -0,0 +1,107 @@
+package translation
+
+import (
+	"pkv/api/src/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_pending(t *testing.T) {
+	german := domain.Description{Title: "Training", Text: "# Training\n\nMontags"}
+	origin := Origin("de", german)
+	languages := []string{"de", "en", "fr", "it"}
+	tests := []struct {
+		name         string
+		descriptions domain.Descriptions
+		wantSource   string
+		wantTargets  []string
+	}{
+		{"nothing written", domain.Descriptions{}, "", nil},
+		{"only translations", domain.Descriptions{"en": {Title: "Training", Translated: true}}, "", nil},
+		{"missing", domain.Descriptions{"de": german}, "de", []string{"en", "fr", "it"}},
+		{"empty", domain.Descriptions{"de": german, "en": {}}, "de", []string{"en", "fr", "it"}},
+		{"up to date", domain.Descriptions{
+			"de": german,
+			"en": {Title: "Training", Translated: true, Source: origin},
+			"fr": {Title: "Entraînement", Translated: true, Source: origin},
+		}, "de", []string{"it"}},
+		{"outdated", domain.Descriptions{
+			"de": german,
+			"en": {Title: "Training", Translated: true, Source: "de:0123"},
+		}, "de", []string{"en", "fr", "it"}},
+		{"written by humans", domain.Descriptions{
+			"en": {Title: "Training"},
+			"fr": {Title: "Entraînement", Source: "de:0123"},
+		}, "en", []string{"de", "it"}},
+		{"unconfigured language", domain.Descriptions{"pl": {Title: "Trening"}}, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, targets := pending(tt.descriptions, languages)
+			if source != tt.wantSource || !reflect.DeepEqual(targets, tt.wantTargets) {
+				t.Errorf("pending() = %v, %v, want %v, %v", source, targets, tt.wantSource, tt.wantTargets)
+			}
+		})
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	d := domain.Description{Title: "Training", Text: "# Training"}
+	if got := Origin("de", d); got != "de:f840b648be74d8948c02814fdb5d539d" {
+		t.Errorf("Origin() = %v, want the language and the MD5 checksum computed by the database", got)
+	}
+	if Origin("de", d) == Origin("de", domain.Description{Title: "Training", Text: "# Training "}) {
+		t.Errorf("Origin() does not change with the text")
+	}
+	if Origin("de", d) == Origin("en", d) {
+		t.Errorf("Origin() does not change with the language")
+	}
+}
+
+func Test_backoff(t *testing.T) {
+	if got := backoff(1); got != 2*time.Minute {
+		t.Errorf("backoff(1) = %v, want 2m", got)
+	}
+	if got := backoff(4); got != 16*time.Minute {
+		t.Errorf("backoff(4) = %v, want 16m", got)
+	}
+	if got := backoff(maxAttempts + 10); got != 24*time.Hour {
+		t.Errorf("backoff() = %v, want at most a day", got)
+	}
+}
+
+func Test_markEdits(t *testing.T) {
+	translated := domain.Description{Title: "Training", Text: "# Training\n\nMondays", Translated: true, Source: "de:0123"}
+	stored := domain.Descriptions{"en": translated, "fr": {Title: "Entraînement", Text: "# Entraînement"}}
+	tests := []struct {
+		name        string
+		description domain.Description
+		stored      domain.Descriptions
+		want        domain.Description
+	}{
+		{"unchanged", translated, stored, translated},
+		{"source sent back", domain.Description{Title: "Training", Text: translated.Text, Translated: true}, stored, translated},
+		{"text corrected", domain.Description{Title: "Training", Text: "# Training\n\nOn Mondays", Translated: true, Source: "de:0123"}, stored,
+			domain.Description{Title: "Training", Text: "# Training\n\nOn Mondays"}},
+		{"title corrected", domain.Description{Title: "Practice", Text: translated.Text, Translated: true}, stored,
+			domain.Description{Title: "Practice", Text: translated.Text}},
+		{"new entity", translated, nil, domain.Description{Title: "Training", Text: translated.Text}},
+		{"written by a human", domain.Description{Title: "Training", Text: "# Training\n\nMondays"}, stored,
+			domain.Description{Title: "Training", Text: "# Training\n\nMondays"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			descriptions := domain.Descriptions{"en": tt.description}
+			markEdits(descriptions, tt.stored)
+			if got := descriptions["en"]; got != tt.want {
+				t.Errorf("markEdits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	french := domain.Descriptions{"fr": {Title: "Entraînement", Text: "# Entraînement", Translated: true}}
+	markEdits(french, stored)
+	if french["fr"].Translated {
+		t.Errorf("markEdits() kept a description claimed translated whose stored one was written by a human")
+	}
+}
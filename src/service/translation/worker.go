@@ -0,0 +1,131 @@
+package translation
+
+import (
+	"context"
+	"log"
+	"pkv/api/src/domain"
+	"pkv/api/src/repository/dpv"
+	"pkv/api/src/repository/t"
+	"pkv/api/src/service/description"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxAttempts = 8                   // failed jobs are kept for inspection afterwards, until the entity is written again
+	batchSize   = 20                  // jobs processed per run
+	keepDone    = 90 * 24 * time.Hour // jobs done are kept this long to sum up the characters translated
+)
+
+// KeepTranslating processes the due translations right away and then in the given interval
+func (s *Service) KeepTranslating(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.TranslateDue(context.Background()); err != nil {
+			log.Printf("periodic translating failed: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+// TranslateDue processes the due translations if translation is enabled, within the translation budget of the current
+// month unless it is 0. Jobs exceeding the remaining budget are deferred to the next month, smaller ones are still
+// processed. Failed translations are retried later with exponential backoff.
+func (s *Service) TranslateDue(ctx context.Context) error {
+	if !dpv.ConfigInstance.Translation.Enabled {
+		return nil
+	}
+	budget := dpv.ConfigInstance.Settings.TranslationBudget
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := s.db.RemoveTranslationJobs(now.Add(-keepDone), ctx); err != nil {
+		return err
+	}
+	used, err := s.db.GetTranslatedCharacters(monthStart(now), ctx)
+	if err != nil {
+		return t.Errorf("reading translation usage failed: %w", err)
+	}
+	jobs, err := s.db.GetDueTranslations(now, maxAttempts, batchSize, ctx)
+	if err != nil {
+		return t.Errorf("reading translations failed: %w", err)
+	}
+	for _, job := range jobs {
+		source, ok, err := s.source(job, ctx)
+		if err != nil {
+			return err
+		}
+		characters := 0
+		if ok {
+			text := description.FixTitle(source.Title, source.Text)
+			characters = utf8.RuneCountInString(text)
+			if budget > 0 && used+characters > budget {
+				log.Printf("translating %d characters of %s/%s exceeds the budget of %d characters, %d translated this month, deferred to next month", characters, job.Collection, job.EntityKey, budget, used)
+				job.Due = monthStart(now).AddDate(0, 1, 0)
+				if err := s.db.UpdateTranslationJob(job, ctx); err != nil {
+					return err
+				}
+				continue
+			}
+			err = s.store(job, source, text, ctx)
+		}
+		if err != nil {
+			job.Attempts++
+			job.Error = err.Error()
+			job.Due = now.Add(backoff(job.Attempts))
+		} else {
+			job.Error = ""
+			job.Done = &now
+			job.Characters = characters
+			used += characters
+		}
+		if err := s.db.UpdateTranslationJob(job, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// source returns the description to translate, or false if the job has become unnecessary because the entity has been
+// deleted or its descriptions have changed
+func (s *Service) source(job domain.TranslationJob, ctx context.Context) (domain.Description, bool, error) {
+	descriptions, err := s.db.GetDescriptions(job.Collection, job.EntityKey, ctx)
+	if err != nil {
+		return domain.Description{}, false, t.Errorf("reading descriptions failed: %w", err)
+	}
+	source, targets := pending(descriptions, languages())
+	if source != job.Source || !slices.Contains(targets, job.Target) {
+		return domain.Description{}, false, nil
+	}
+	return descriptions[source], true, nil
+}
+
+// store translates the text of the source description, with its title as heading, and stores the result as automatic
+// translation
+func (s *Service) store(job domain.TranslationJob, source domain.Description, text string, ctx context.Context) error {
+	text, err := description.TranslateMarkdown(s.translator, dpv.ConfigInstance.Translation.Glossary, text, job.Source, job.Target, ctx)
+	if err != nil {
+		return err
+	}
+	translation := domain.Descriptions{job.Target: {
+		Title:      source.Title,
+		Text:       text,
+		Translated: true,
+		Source:     Origin(job.Source, source),
+	}}
+	description.RenderAll(translation, s.db, ctx)
+	if _, err := s.db.StoreTranslation(job, translation[job.Target], ctx); err != nil {
+		return t.Errorf("storing translation failed: %w", err)
+	}
+	return nil
+}
+
+// backoff returns how long to wait before the next attempt after the given number of failed ones, doubling from two
+// minutes up to a day
+func backoff(attempts int) time.Duration {
+	return min(time.Minute<<attempts, 24*time.Hour)
+}
+
+func monthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}